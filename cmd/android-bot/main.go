@@ -77,7 +77,7 @@ func script(bot common.Bot) error {
     // }
 
     // 发送按键事件（示例）
-    // err = androidBot.SendKeyEvent(4) // 返回键
+    // err = androidBot.SendKeyEvent(androidbot.KeyCodeBack) // 返回键
     // if err != nil {
     //     return fmt.Errorf("failed to send key event: %v", err)
     // }
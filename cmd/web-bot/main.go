@@ -25,25 +25,25 @@ func script(bot common.Bot) error {
     }
 
     // 查找元素示例
-    // element, err := webBot.FindElement("css selector", "input[type='text']")
+    // element, err := webBot.FindElement(webbot.CSS("input[type='text']"))
     // if err != nil {
     //     return fmt.Errorf("failed to find element: %v", err)
     // }
 
     // 点击元素示例
-    // err = webBot.ClickElement(element)
+    // err = webBot.ClickElement(element.Locator)
     // if err != nil {
     //     return fmt.Errorf("failed to click element: %v", err)
     // }
 
     // 输入文本示例
-    // err = webBot.InputText(element, "Hello, World!")
+    // err = webBot.InputText(element.Locator, "Hello, World!")
     // if err != nil {
     //     return fmt.Errorf("failed to input text: %v", err)
     // }
 
     // 获取元素文本示例
-    // text, err := webBot.GetElementText(element)
+    // text, err := webBot.GetElementText(element.Locator)
     // if err != nil {
     //     return fmt.Errorf("failed to get element text: %v", err)
     // }
@@ -1,7 +1,15 @@
 // Package androidbot 提供Android平台自动化的功能和接口
 package androidbot
 
-import "github.com/zhangsan-ai/go-aibote/pkg/common"
+import (
+    "context"
+    "errors"
+    "fmt"
+    "image"
+    "time"
+
+    "github.com/zhangsan-ai/go-aibote/pkg/common"
+)
 
 // Task 表示Android设备上的一个任务
 type Task struct {
@@ -15,7 +23,8 @@ type Task struct {
 type AndroidBot interface {
     common.Bot
     
-    // RecentTasks 显示手机最近任务列表
+    // RecentTasks 获取手机最近任务列表
+    // 需要在屏幕上打开最近任务界面时调用Recents
     RecentTasks() ([]Task, error)
     
     // Tap 在指定坐标点点击
@@ -25,7 +34,8 @@ type AndroidBot interface {
     Swipe(x1, y1, x2, y2 int) error
     
     // GetInstalledPackages 获取已安装的应用包列表
-    GetInstalledPackages() ([]string, error)
+    // filter: 可选的过滤条件，如androidbot.PackagesThirdParty，不传表示所有应用，最多传一个
+    GetInstalledPackages(filter ...PackageFilter) ([]string, error)
     
     // StartApp 启动指定的应用
     StartApp(packageName string) error
@@ -34,20 +44,217 @@ type AndroidBot interface {
     StopApp(packageName string) error
     
     // TakeScreenshot 截取当前屏幕
+    // outputPath: 设备上保存截图的路径，需要在本地使用截图时调用Screenshot
     TakeScreenshot(outputPath string) error
     
-    // FindElementByXPath 通过XPath查找元素
+    // FindElementByXPath 通过XPath查找元素，返回元素中心点坐标，没有找到时返回(-1, -1)
+    // 表达式在本地的层级树上执行，只支持XPath 1.0的一个子集：
+    // 路径、括号表达式如(//android.widget.Button)[1]、child/descendant/self/parent/ancestor/sibling轴、
+    // 谓词中的属性、比较、算术和contains、starts-with、not、last等常用函数，
+    // 不支持并集"|"、following/preceding轴和属性轴，使用不支持的语法时返回错误
     FindElementByXPath(xpath string) (int, int, error)
     
     // FindColorByRGB 在屏幕上查找指定颜色
     FindColorByRGB(r, g, b, precision int) ([][2]int, error)
     
     // SendKeyEvent 发送按键事件
-    SendKeyEvent(keyCode int) error
+    // keyCode: 按键码，如androidbot.KeyCodeBack
+    SendKeyEvent(keyCode KeyCode) error
     
     // InputText 输入文本
     InputText(text string) error
     
+    // DumpHierarchy 获取当前界面的层级结构
+    // 返回解析后的节点树，可以在本地执行任意多次XPath或条件查询，不需要再与设备通信
+    DumpHierarchy() (*Hierarchy, error)
+    
+    // FindElement 查找第一个匹配定位器的元素
+    // selector: 元素定位器，如androidbot.ResourceID("login")、androidbot.Text("确定")
+    // 返回元素句柄，没有匹配的元素时返回*ElementNotFoundError
+    // 句柄在每次操作前重新查找元素，不需要担心布局变化导致坐标失效
+    FindElement(selector Selector) (*AndroidElement, error)
+    
+    // FindElements 查找所有匹配定位器的元素，没有匹配时返回空切片
+    FindElements(selector Selector) ([]*AndroidElement, error)
+    
+    // ScrollIntoView 滚动界面直到匹配定位器的元素完整显示
+    // 适用于列表中尚未加载到界面上的元素
+    ScrollIntoView(selector Selector) (*AndroidElement, error)
+    
+    // LongPress 在指定坐标点长按
+    // duration: 按住的时间
+    LongPress(x, y int, duration time.Duration) error
+    
+    // SwipeWithDuration 按指定时间和缓动函数滑动屏幕
+    // easing: 缓动函数，如androidbot.EaseOutQuad，nil表示匀速
+    SwipeWithDuration(x1, y1, x2, y2 int, duration time.Duration, easing Easing) error
+    
+    // Pinch 以(cx, cy)为中心做双指缩放
+    // fromRadius, toRadius: 手指到中心的起止距离，fromRadius大于toRadius为捏合(缩小)，反之为张开(放大)
+    Pinch(cx, cy, fromRadius, toRadius int, duration time.Duration) error
+    
+    // Rotate 以(cx, cy)为中心做双指旋转
+    // radius: 手指到中心的距离
+    // degrees: 旋转角度，正数为顺时针
+    Rotate(cx, cy, radius int, degrees float64, duration time.Duration) error
+    
+    // PerformGesture 执行自定义的多指手势
+    // paths: 每根手指的轨迹，所有手指作为一个手势同时执行，最多10根手指
+    // 例如：两根手指先后按下的拖动可以用不同的起始时间表示
+    // 每根手指的轨迹持续时间必须大于0，点击可以用同一位置、时间不同的两个点表示
+    PerformGesture(paths ...GesturePath) error
+    
+    // WaitForElement 等待匹配定位器的元素出现
+    // ctx: 用于取消等待，取消时返回ctx.Err()
+    // timeout: 最长等待时间，0表示使用WithWaitTimeout设置的默认值
+    // 超时返回*WaitTimeoutError
+    WaitForElement(ctx context.Context, selector Selector, timeout time.Duration) (*AndroidElement, error)
+    
+    // WaitForElementGone 等待匹配定位器的元素全部消失，如加载提示
+    WaitForElementGone(ctx context.Context, selector Selector, timeout time.Duration) error
+    
+    // WaitForText 等待元素出现且文本满足条件
+    // match: 文本判断函数，例如func(s string) bool { return strings.Contains(s, "成功") }
+    WaitForText(ctx context.Context, selector Selector, match func(text string) bool, timeout time.Duration) (*AndroidElement, error)
+    
+    // WaitForActivity 等待指定应用或Activity进入前台
+    // activity: 完整类名或".MainActivity"形式的简写，为空表示只判断包名
+    WaitForActivity(ctx context.Context, packageName, activity string, timeout time.Duration) error
+    
+    // WaitForStable 等待界面稳定，即连续两次转储的层级完全相同
+    // 返回最后一次转储的层级，可以直接用于后续查询
+    WaitForStable(ctx context.Context, timeout time.Duration) (*Hierarchy, error)
+    
+    // CurrentApp 获取当前位于前台的应用包名和Activity
+    // 可以在点击前确认处于正确的应用，或者发现其他应用的弹窗抢占了前台
+    CurrentApp() (ForegroundApp, error)
+    
+    // IsScreenOn 判断屏幕是否点亮
+    IsScreenOn() (bool, error)
+    
+    // IsLocked 判断是否处于锁屏状态
+    IsLocked() (bool, error)
+    
+    // GetOrientation 获取屏幕方向
+    GetOrientation() (Orientation, error)
+    
+    // GetDisplayInfo 获取屏幕尺寸、密度和方向
+    GetDisplayInfo() (DisplayInfo, error)
+    
+    // GetPackageInfo 获取已安装应用的版本和安装时间
+    // 应用未安装时返回的错误可以通过errors.Is(err, ErrPackageNotFound)判断
+    GetPackageInfo(packageName string) (PackageInfo, error)
+    
+    // InstallAPK 将脚本所在主机上的APK文件发送到设备并安装
+    // apkPath: 本地APK文件路径，文件内容通过连接分块发送
+    InstallAPK(apkPath string, options InstallOptions) error
+    
+    // UninstallApp 卸载应用
+    // keepData: 是否保留应用数据和缓存
+    UninstallApp(packageName string, keepData bool) error
+    
+    // ClearAppData 清除应用的所有数据和缓存，相当于恢复到刚安装的状态
+    ClearAppData(packageName string) error
+    
+    // ClearAppCache 只清除应用的缓存，保留登录状态等数据
+    ClearAppCache(packageName string) error
+    
+    // GrantPermission 授予应用运行时权限
+    // permission: 权限名，如"android.permission.CAMERA"，也可以简写为"CAMERA"
+    GrantPermission(packageName, permission string) error
+    
+    // RevokePermission 撤销应用的运行时权限
+    RevokePermission(packageName, permission string) error
+    
+    // StartActivity 按Intent启动Activity
+    // 可以直接打开指定页面，例如：
+    // bot.StartActivity(androidbot.Intent{Component: "com.example/.OrderActivity", Extras: map[string]interface{}{"orderId": int64(42)}})
+    StartActivity(intent Intent) error
+    
+    // OpenDeepLink 打开深度链接
+    // uri: 链接地址，如"myapp://orders/42"或"https://example.com/orders/42"
+    // packageName: 处理链接的应用，为空时由系统选择
+    OpenDeepLink(uri string, packageName string) error
+    
+    // SendBroadcast 发送广播
+    SendBroadcast(intent Intent) error
+    
+    // PushFile 将脚本所在主机上的文件发送到设备
+    // devicePath: 设备上的绝对路径，如"/sdcard/Download/fixture.csv"
+    // 传输完成后比较两端的SHA-256，不一致时返回*ChecksumMismatchError，驱动程序没有返回校验和时返回ErrChecksumUnavailable
+    PushFile(localPath, devicePath string) error
+    
+    // PullFile 将设备上的文件保存到脚本所在主机
+    // 校验失败时不会留下不完整的本地文件
+    PullFile(devicePath, localPath string) error
+    
+    // ReadFile 读取设备上的文件内容到内存
+    ReadFile(devicePath string) ([]byte, error)
+    
+    // ListFiles 列出设备目录中的文件和子目录
+    ListFiles(dir string) ([]DeviceFile, error)
+    
+    // DeleteFile 删除设备上的文件或目录，目录会连同内容一起删除
+    DeleteFile(devicePath string) error
+    
+    // MakeDir 在设备上创建目录，父目录不存在时一并创建
+    MakeDir(devicePath string) error
+    
+    // Screenshot 截取当前屏幕并返回图像，不在设备上留下文件
+    Screenshot() (image.Image, error)
+    
+    // ListNotifications 获取通知栏中当前的所有通知
+    ListNotifications() ([]Notification, error)
+    
+    // WaitForNotification 等待一条满足条件的新通知
+    // 调用前已经存在且没有更新的通知不会被返回，避免读到旧的验证码
+    // 例如：bot.WaitForNotification(ctx, func(n androidbot.Notification) bool { return n.Code() != "" }, time.Minute)
+    WaitForNotification(ctx context.Context, match func(Notification) bool, timeout time.Duration) (Notification, error)
+    
+    // TapNotification 点击通知，打开通知对应的页面
+    TapNotification(n Notification) error
+    
+    // DismissNotification 移除通知
+    DismissNotification(n Notification) error
+    
+    // OpenNotifications 展开通知栏
+    OpenNotifications() error
+    
+    // CloseNotifications 收起通知栏
+    CloseNotifications() error
+    
+    // LongPressKey 长按按键
+    // duration: 按住的时间，0表示使用默认的1秒
+    LongPressKey(keyCode KeyCode, duration time.Duration) error
+    
+    // SendKeyCombo 发送带修饰键的按键事件
+    // meta: 修饰键状态，如androidbot.MetaCtrlOn，Ctrl+A可以写成SendKeyCombo(androidbot.KeyCodeA, androidbot.MetaCtrlOn)
+    SendKeyCombo(keyCode KeyCode, meta MetaState) error
+    
+    // Back 按返回键
+    Back() error
+    
+    // Home 按主屏幕键
+    Home() error
+    
+    // Recents 打开最近任务界面
+    Recents() error
+    
+    // Power 按电源键
+    Power() error
+    
+    // VolumeUp 按音量加键
+    VolumeUp() error
+    
+    // VolumeDown 按音量减键
+    VolumeDown() error
+    
+    // Enter 按回车键
+    Enter() error
+    
+    // Delete 按删除键，删除光标前的一个字符
+    Delete() error
+    
     // 其他Android特定方法将在后续实现
 }
 
@@ -66,7 +273,9 @@ func NewAndroidBot(options ...AndroidBotOption) (AndroidBot, error) {
     // 创建AndroidBot实现
     bot := &androidBotImpl{
         // 设置默认值
-        qt: nil, // 默认无Qt对象
+        qt:           nil,                 // 默认无Qt对象
+        waitTimeout:  defaultWaitTimeout,  // 默认等待10秒
+        pollInterval: defaultPollInterval, // 默认每0.5秒检查一次
     }
     
     // 应用所有选项
@@ -82,7 +291,9 @@ func NewAndroidBot(options ...AndroidBotOption) (AndroidBot, error) {
 
 // androidBotImpl 是AndroidBot接口的具体实现
 type androidBotImpl struct {
-    qt interface{}
+    qt           interface{}
+    waitTimeout  time.Duration
+    pollInterval time.Duration
     // 其他必要的字段将在后续实现中添加
 }
 
@@ -121,14 +332,25 @@ func (b *androidBotImpl) Tap(x, y int) error {
 }
 
 // 实现AndroidBot接口的Swipe方法
+// 以默认的300毫秒匀速滑动
 func (b *androidBotImpl) Swipe(x1, y1, x2, y2 int) error {
-    // 实际实现将在后续添加
-    // 这里返回nil作为占位符
-    return nil
+    return b.SwipeWithDuration(x1, y1, x2, y2, defaultSwipeDuration, EaseLinear)
 }
 
 // 实现AndroidBot接口的GetInstalledPackages方法
-func (b *androidBotImpl) GetInstalledPackages() ([]string, error) {
+func (b *androidBotImpl) GetInstalledPackages(filters ...PackageFilter) ([]string, error) {
+    if len(filters) > 1 {
+        return nil, errors.New("androidbot: at most one package filter is allowed")
+    }
+    filter := PackagesAll
+    if len(filters) == 1 {
+        filter = filters[0]
+    }
+    switch filter {
+    case PackagesAll, PackagesSystem, PackagesThirdParty:
+    default:
+        return nil, fmt.Errorf("androidbot: invalid package filter %d", filter)
+    }
     // 实际实现将在后续添加
     // 这里返回空切片和nil作为占位符
     return []string{}, nil
@@ -156,10 +378,24 @@ func (b *androidBotImpl) TakeScreenshot(outputPath string) error {
 }
 
 // 实现AndroidBot接口的FindElementByXPath方法
+// 没有找到元素时返回(-1,-1)和nil
 func (b *androidBotImpl) FindElementByXPath(xpath string) (int, int, error) {
-    // 实际实现将在后续添加
-    // 这里返回(-1,-1)和nil作为占位符
-    return -1, -1, nil
+    if _, err := compileXPath(xpath); err != nil {
+        return -1, -1, err
+    }
+    hierarchy, err := b.DumpHierarchy()
+    if err != nil {
+        return -1, -1, err
+    }
+    node, err := hierarchy.Find(xpath)
+    if errors.Is(err, ErrNodeNotFound) {
+        return -1, -1, nil
+    }
+    if err != nil {
+        return -1, -1, err
+    }
+    x, y := node.Center()
+    return x, y, nil
 }
 
 // 实现AndroidBot接口的FindColorByRGB方法
@@ -170,7 +406,10 @@ func (b *androidBotImpl) FindColorByRGB(r, g, blue, precision int) ([][2]int, er
 }
 
 // 实现AndroidBot接口的SendKeyEvent方法
-func (b *androidBotImpl) SendKeyEvent(keyCode int) error {
+func (b *androidBotImpl) SendKeyEvent(keyCode KeyCode) error {
+    if !keyCode.IsValid() {
+        return fmt.Errorf("androidbot: invalid key code %s", keyCode)
+    }
     // 实际实现将在后续添加
     // 这里返回nil作为占位符
     return nil
@@ -0,0 +1,116 @@
+package androidbot
+
+import "fmt"
+
+// ForegroundApp 表示当前位于前台的应用
+// Activity: Activity的完整类名，如"com.example.app.MainActivity"
+type ForegroundApp struct {
+    PackageName string
+    Activity    string
+}
+
+// String 返回"包名/Activity"形式的文本，与am start -n的组件名格式一致
+func (a ForegroundApp) String() string {
+    return a.PackageName + "/" + a.Activity
+}
+
+// Is 判断前台应用是否为指定的应用或Activity
+// activity: 完整类名或".MainActivity"形式的简写，为空表示只判断包名
+func (a ForegroundApp) Is(packageName, activity string) bool {
+    if a.PackageName != packageName {
+        return false
+    }
+    return activity == "" || activityMatches(packageName, a.Activity, activity)
+}
+
+// Orientation 表示屏幕方向，取值与Android的Surface.ROTATION_*一致
+type Orientation int
+
+const (
+    OrientationPortrait         Orientation = 0 // 竖屏
+    OrientationLandscape        Orientation = 1 // 逆时针旋转90度的横屏
+    OrientationReversePortrait  Orientation = 2 // 倒置竖屏
+    OrientationReverseLandscape Orientation = 3 // 顺时针旋转90度的横屏
+)
+
+// IsLandscape 判断是否为横屏
+func (o Orientation) IsLandscape() bool {
+    return o == OrientationLandscape || o == OrientationReverseLandscape
+}
+
+// String 返回屏幕方向的名称
+func (o Orientation) String() string {
+    switch o {
+    case OrientationPortrait:
+        return "portrait"
+    case OrientationLandscape:
+        return "landscape"
+    case OrientationReversePortrait:
+        return "reverse-portrait"
+    case OrientationReverseLandscape:
+        return "reverse-landscape"
+    }
+    return fmt.Sprintf("orientation(%d)", int(o))
+}
+
+// DisplayInfo 表示屏幕的显示参数
+// Width, Height: 当前方向下的屏幕像素宽高
+// Density: 屏幕密度(dpi)，如440
+type DisplayInfo struct {
+    Width       int
+    Height      int
+    Density     int
+    Orientation Orientation
+}
+
+// Scale 返回密度缩放比例，即1dp对应的像素数
+func (d DisplayInfo) Scale() float64 {
+    return float64(d.Density) / 160
+}
+
+// DpToPx 将dp转换为像素
+func (d DisplayInfo) DpToPx(dp float64) int {
+    return int(dp*d.Scale() + 0.5)
+}
+
+// 实现AndroidBot接口的CurrentApp方法
+func (b *androidBotImpl) CurrentApp() (ForegroundApp, error) {
+    // 实际实现将在后续添加
+    // 这里返回空的ForegroundApp和nil作为占位符
+    return ForegroundApp{}, nil
+}
+
+// 实现AndroidBot接口的IsScreenOn方法
+func (b *androidBotImpl) IsScreenOn() (bool, error) {
+    // 实际实现将在后续添加
+    // 这里返回false和nil作为占位符
+    return false, nil
+}
+
+// 实现AndroidBot接口的IsLocked方法
+func (b *androidBotImpl) IsLocked() (bool, error) {
+    // 实际实现将在后续添加
+    // 这里返回false和nil作为占位符
+    return false, nil
+}
+
+// 实现AndroidBot接口的GetOrientation方法
+// 屏幕方向取自界面层级转储的rotation属性
+func (b *androidBotImpl) GetOrientation() (Orientation, error) {
+    hierarchy, err := b.DumpHierarchy()
+    if err != nil {
+        return OrientationPortrait, err
+    }
+    return Orientation(hierarchy.Rotation), nil
+}
+
+// 实现AndroidBot接口的GetDisplayInfo方法
+func (b *androidBotImpl) GetDisplayInfo() (DisplayInfo, error) {
+    orientation, err := b.GetOrientation()
+    if err != nil {
+        return DisplayInfo{}, err
+    }
+    // 获取屏幕尺寸和密度的实际实现将在后续添加
+    // 这里只返回屏幕方向作为占位符
+    return DisplayInfo{Orientation: orientation}, nil
+}
@@ -0,0 +1,417 @@
+package androidbot
+
+import (
+    "bytes"
+    "fmt"
+    "strings"
+    "time"
+)
+
+// 滚动查找元素的默认参数
+const (
+    maxScrollSwipes = 10
+    scrollSettle    = 300 * time.Millisecond
+    longClickTime   = time.Second
+)
+
+// Strategy 表示Android元素的定位策略
+type Strategy string
+
+const (
+    StrategyXPath       Strategy = "xpath"
+    StrategyResourceID  Strategy = "id"
+    StrategyText        Strategy = "text"
+    StrategyDescription Strategy = "desc"
+    StrategyClass       Strategy = "class"
+)
+
+// Selector 表示一个Android元素定位器
+// 例如：androidbot.ResourceID("com.example:id/login")
+type Selector struct {
+    Strategy Strategy
+    Value    string
+}
+
+// XPath 创建一个XPath定位器
+func XPath(expr string) Selector {
+    return Selector{Strategy: StrategyXPath, Value: expr}
+}
+
+// ResourceID 创建一个按resource-id定位的定位器
+// 可以只写id名，如"login"，也可以写完整形式，如"com.example:id/login"
+func ResourceID(id string) Selector {
+    return Selector{Strategy: StrategyResourceID, Value: id}
+}
+
+// Text 创建一个按文本完全匹配定位的定位器
+func Text(text string) Selector {
+    return Selector{Strategy: StrategyText, Value: text}
+}
+
+// Description 创建一个按content-desc完全匹配定位的定位器
+func Description(desc string) Selector {
+    return Selector{Strategy: StrategyDescription, Value: desc}
+}
+
+// ClassName 创建一个按class定位的定位器，如"android.widget.EditText"
+func ClassName(class string) Selector {
+    return Selector{Strategy: StrategyClass, Value: class}
+}
+
+// String 返回"策略=值"形式的文本
+func (s Selector) String() string {
+    return string(s.Strategy) + "=" + s.Value
+}
+
+// match 判断节点是否匹配非XPath定位器
+func (s Selector) match(n *Node) bool {
+    switch s.Strategy {
+    case StrategyResourceID:
+        return n.ResourceID == s.Value || strings.HasSuffix(n.ResourceID, ":id/"+s.Value)
+    case StrategyText:
+        return n.Text == s.Value
+    case StrategyDescription:
+        return n.ContentDesc == s.Value
+    case StrategyClass:
+        return n.Class == s.Value
+    }
+    return false
+}
+
+// validate 检查定位器是否有效
+func (s Selector) validate() error {
+    switch s.Strategy {
+    case StrategyXPath:
+        _, err := compileXPath(s.Value)
+        return err
+    case StrategyResourceID, StrategyText, StrategyDescription, StrategyClass:
+        if s.Value == "" {
+            return fmt.Errorf("invalid selector %q: value is required", s)
+        }
+        return nil
+    }
+    return fmt.Errorf("invalid selector %q: unknown strategy", s)
+}
+
+// nodes 返回层级中所有匹配定位器的节点
+func (s Selector) nodes(h *Hierarchy) ([]*Node, error) {
+    if s.Strategy == StrategyXPath {
+        return h.FindAll(s.Value)
+    }
+    if err := s.validate(); err != nil {
+        return nil, err
+    }
+    // 根元素hierarchy不是界面节点，从它的子节点开始匹配
+    var result []*Node
+    for _, child := range h.Root.Children {
+        result = append(result, child.Filter(s.match)...)
+    }
+    return result, nil
+}
+
+// ElementNotFoundError 表示当前界面上没有匹配定位器的元素
+// 可以通过errors.Is(err, ErrNodeNotFound)判断
+type ElementNotFoundError struct {
+    Selector Selector
+    Index    int
+}
+
+func (e *ElementNotFoundError) Error() string {
+    if e.Index > 0 {
+        return fmt.Sprintf("androidbot: element %s[%d] not found", e.Selector, e.Index)
+    }
+    return fmt.Sprintf("androidbot: element %s not found", e.Selector)
+}
+
+func (e *ElementNotFoundError) Is(target error) bool {
+    return target == ErrNodeNotFound
+}
+
+// AndroidElement 是界面元素的句柄
+// 它保存定位器和最近一次查找到的节点快照
+// 每个操作执行前都会重新转储界面并查找元素，因此布局变化后句柄依然有效
+// 属性方法返回最近一次查找时的快照，需要最新值时先调用Refresh
+type AndroidElement struct {
+    bot      *androidBotImpl
+    selector Selector
+    index    int
+    node     *Node
+}
+
+// Selector 返回元素的定位器
+func (e *AndroidElement) Selector() Selector {
+    return e.selector
+}
+
+// Node 返回最近一次查找到的节点快照
+func (e *AndroidElement) Node() *Node {
+    return e.node
+}
+
+// Refresh 重新转储界面并查找元素，更新节点快照
+// 元素已经消失时返回*ElementNotFoundError
+func (e *AndroidElement) Refresh() error {
+    hierarchy, err := e.bot.DumpHierarchy()
+    if err != nil {
+        return err
+    }
+    return e.resolveIn(hierarchy)
+}
+
+// resolveIn 在给定的层级中查找元素
+func (e *AndroidElement) resolveIn(h *Hierarchy) error {
+    nodes, err := e.selector.nodes(h)
+    if err != nil {
+        return err
+    }
+    if e.index >= len(nodes) {
+        return &ElementNotFoundError{Selector: e.selector, Index: e.index}
+    }
+    e.node = nodes[e.index]
+    return nil
+}
+
+// Text 返回元素的text属性
+func (e *AndroidElement) Text() string {
+    return e.node.Text
+}
+
+// ResourceID 返回元素的resource-id属性
+func (e *AndroidElement) ResourceID() string {
+    return e.node.ResourceID
+}
+
+// ContentDesc 返回元素的content-desc属性
+func (e *AndroidElement) ContentDesc() string {
+    return e.node.ContentDesc
+}
+
+// Class 返回元素的类名
+func (e *AndroidElement) Class() string {
+    return e.node.Class
+}
+
+// Package 返回元素所属应用的包名
+func (e *AndroidElement) Package() string {
+    return e.node.Package
+}
+
+// Bounds 返回元素在屏幕上的区域
+func (e *AndroidElement) Bounds() Bounds {
+    return e.node.Bounds
+}
+
+// Attribute 返回元素的原始属性值
+func (e *AndroidElement) Attribute(name string) (string, bool) {
+    return e.node.Attr(name)
+}
+
+// IsClickable 判断元素是否可点击
+func (e *AndroidElement) IsClickable() bool {
+    return e.node.Clickable
+}
+
+// IsChecked 判断元素是否被勾选
+func (e *AndroidElement) IsChecked() bool {
+    return e.node.Checked
+}
+
+// IsEnabled 判断元素是否可用
+func (e *AndroidElement) IsEnabled() bool {
+    return e.node.Enabled
+}
+
+// IsFocused 判断元素是否获得焦点
+func (e *AndroidElement) IsFocused() bool {
+    return e.node.Focused
+}
+
+// IsScrollable 判断元素是否可滚动
+func (e *AndroidElement) IsScrollable() bool {
+    return e.node.Scrollable
+}
+
+// IsSelected 判断元素是否被选中
+func (e *AndroidElement) IsSelected() bool {
+    return e.node.Selected
+}
+
+// Click 重新查找元素后点击其中心
+func (e *AndroidElement) Click() error {
+    if err := e.Refresh(); err != nil {
+        return err
+    }
+    x, y := e.node.Center()
+    return e.bot.Tap(x, y)
+}
+
+// LongClick 重新查找元素后在其中心长按
+func (e *AndroidElement) LongClick() error {
+    if err := e.Refresh(); err != nil {
+        return err
+    }
+    x, y := e.node.Center()
+    return e.bot.LongPress(x, y, longClickTime)
+}
+
+// Clear 清空输入框的内容
+// 点击输入框获得焦点后全选并删除，不依赖点击时光标的位置
+// 部分输入法不支持全选时，再将光标移到末尾逐个删除剩余的字符
+func (e *AndroidElement) Clear() error {
+    if err := e.Click(); err != nil {
+        return err
+    }
+    if err := e.bot.SendKeyCombo(KeyCodeA, MetaCtrlOn); err != nil {
+        return err
+    }
+    if err := e.bot.SendKeyEvent(KeyCodeDel); err != nil {
+        return err
+    }
+    if err := e.Refresh(); err != nil {
+        return err
+    }
+    if e.node.Text == "" {
+        return nil
+    }
+    if err := e.bot.SendKeyEvent(KeyCodeMoveEnd); err != nil {
+        return err
+    }
+    for range []rune(e.node.Text) {
+        if err := e.bot.SendKeyEvent(KeyCodeDel); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// SetText 清空输入框后输入文本
+func (e *AndroidElement) SetText(text string) error {
+    if err := e.Clear(); err != nil {
+        return err
+    }
+    return e.bot.InputText(text)
+}
+
+// ScrollIntoView 滚动所在的可滚动容器，直到元素完整显示在容器内
+func (e *AndroidElement) ScrollIntoView() error {
+    found, err := e.bot.scrollUntil(e.selector, e.index)
+    if err != nil {
+        return err
+    }
+    e.node = found.node
+    return nil
+}
+
+// 实现AndroidBot接口的FindElement方法
+func (b *androidBotImpl) FindElement(selector Selector) (*AndroidElement, error) {
+    if err := selector.validate(); err != nil {
+        return nil, err
+    }
+    element := &AndroidElement{bot: b, selector: selector}
+    if err := element.Refresh(); err != nil {
+        return nil, err
+    }
+    return element, nil
+}
+
+// 实现AndroidBot接口的FindElements方法
+func (b *androidBotImpl) FindElements(selector Selector) ([]*AndroidElement, error) {
+    if err := selector.validate(); err != nil {
+        return nil, err
+    }
+    hierarchy, err := b.DumpHierarchy()
+    if err != nil {
+        return nil, err
+    }
+    nodes, err := selector.nodes(hierarchy)
+    if err != nil {
+        return nil, err
+    }
+    elements := make([]*AndroidElement, len(nodes))
+    for i, node := range nodes {
+        elements[i] = &AndroidElement{bot: b, selector: selector, index: i, node: node}
+    }
+    return elements, nil
+}
+
+// 实现AndroidBot接口的ScrollIntoView方法
+func (b *androidBotImpl) ScrollIntoView(selector Selector) (*AndroidElement, error) {
+    if err := selector.validate(); err != nil {
+        return nil, err
+    }
+    return b.scrollUntil(selector, 0)
+}
+
+// scrollUntil 滚动界面直到第index个匹配元素完整显示
+// 先向下滚动，到达底部(界面不再变化)后向上滚动，两个方向各最多maxScrollSwipes次
+func (b *androidBotImpl) scrollUntil(selector Selector, index int) (*AndroidElement, error) {
+    element := &AndroidElement{bot: b, selector: selector, index: index}
+    for _, forward := range []bool{true, false} {
+        var last []byte
+        for i := 0; i <= maxScrollSwipes; i++ {
+            hierarchy, err := b.DumpHierarchy()
+            if err != nil {
+                return nil, err
+            }
+            container := scrollContainer(hierarchy, nil)
+            if err := element.resolveIn(hierarchy); err == nil {
+                container = scrollContainer(hierarchy, element.node)
+                if container == nil || isFullyInside(element.node.Bounds, container.Bounds) {
+                    return element, nil
+                }
+            }
+            if container == nil {
+                break
+            }
+            if last != nil && bytes.Equal(last, hierarchy.XML) {
+                // 界面不再变化，已经滚动到尽头
+                break
+            }
+            last = hierarchy.XML
+            if err := b.scrollContainerOnce(container.Bounds, forward); err != nil {
+                return nil, err
+            }
+            time.Sleep(scrollSettle)
+        }
+    }
+    return nil, &ElementNotFoundError{Selector: selector, Index: index}
+}
+
+// scrollContainer 返回节点最近的可滚动祖先
+// node为nil时返回界面中面积最大的可滚动节点
+func scrollContainer(h *Hierarchy, node *Node) *Node {
+    if node != nil {
+        for p := node.Parent; p != nil; p = p.Parent {
+            if p.Scrollable {
+                return p
+            }
+        }
+        return nil
+    }
+    var best *Node
+    h.Root.Walk(func(n *Node) bool {
+        if n.Scrollable && (best == nil || n.Bounds.Width()*n.Bounds.Height() > best.Bounds.Width()*best.Bounds.Height()) {
+            best = n
+        }
+        return true
+    })
+    return best
+}
+
+// isFullyInside 判断inner区域是否完整位于outer区域内
+func isFullyInside(inner, outer Bounds) bool {
+    return inner.Left >= outer.Left && inner.Top >= outer.Top && inner.Right <= outer.Right && inner.Bottom <= outer.Bottom
+}
+
+// scrollContainerOnce 在容器内滑动一次
+// forward为true时向上滑动手指，内容向下滚动；反之内容向上滚动
+// 滑动距离为容器高度的60%，起止点离开边缘，避免触发下拉刷新或通知栏
+func (b *androidBotImpl) scrollContainerOnce(r Bounds, forward bool) error {
+    x := (r.Left + r.Right) / 2
+    top := r.Top + r.Height()*2/10
+    bottom := r.Top + r.Height()*8/10
+    if forward {
+        return b.Swipe(x, bottom, x, top)
+    }
+    return b.Swipe(x, top, x, bottom)
+}
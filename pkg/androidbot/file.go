@@ -0,0 +1,238 @@
+package androidbot
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "image"
+    "image/png"
+    "io"
+    "math/rand"
+    "os"
+    "path"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// screenshotDir 是设备上临时保存截图的目录
+const screenshotDir = "/sdcard/Pictures"
+
+// ErrChecksumUnavailable 表示驱动程序没有返回设备端的校验和，无法确认文件传输完整
+var ErrChecksumUnavailable = errors.New("androidbot: device did not report a checksum")
+
+// ErrEmptyScreenshot 表示设备返回了空的截图
+var ErrEmptyScreenshot = errors.New("androidbot: device returned an empty screenshot")
+
+// DeviceFile 表示设备存储上的一个文件或目录
+type DeviceFile struct {
+    Path    string
+    Name    string
+    Size    int64
+    Mode    os.FileMode
+    ModTime time.Time
+    IsDir   bool
+}
+
+// ChecksumMismatchError 表示传输后两端文件的SHA-256校验和不一致
+type ChecksumMismatchError struct {
+    Path   string
+    Local  string
+    Remote string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+    return fmt.Sprintf("androidbot: checksum mismatch for %s: local %s, device %s", e.Path, e.Local, e.Remote)
+}
+
+// validateDevicePath 检查设备路径是否为绝对路径，并返回清理后的路径
+func validateDevicePath(p string) (string, error) {
+    if !strings.HasPrefix(p, "/") {
+        return "", fmt.Errorf("androidbot: device path %q must be absolute", p)
+    }
+    return path.Clean(p), nil
+}
+
+// verifyChecksum 比较本地和设备上的校验和
+// 驱动程序没有返回校验和时返回ErrChecksumUnavailable，不把未经校验的传输当作成功
+func verifyChecksum(devicePath, local, remote string) error {
+    if remote == "" {
+        return fmt.Errorf("failed to verify %s: %w", devicePath, ErrChecksumUnavailable)
+    }
+    if !strings.EqualFold(local, remote) {
+        return &ChecksumMismatchError{Path: devicePath, Local: local, Remote: remote}
+    }
+    return nil
+}
+
+// 实现AndroidBot接口的PushFile方法
+func (b *androidBotImpl) PushFile(localPath, devicePath string) error {
+    devicePath, err := validateDevicePath(devicePath)
+    if err != nil {
+        return err
+    }
+    f, err := os.Open(localPath)
+    if err != nil {
+        return fmt.Errorf("failed to open local file: %w", err)
+    }
+    defer f.Close()
+    info, err := f.Stat()
+    if err != nil {
+        return fmt.Errorf("failed to open local file: %w", err)
+    }
+    if info.IsDir() {
+        return fmt.Errorf("androidbot: %s is a directory", localPath)
+    }
+    // 边发送边计算校验和，大文件不需要读两遍
+    hash := sha256.New()
+    remote, err := b.writeDeviceFile(devicePath, info.Size(), io.TeeReader(f, hash))
+    if err != nil {
+        return fmt.Errorf("failed to push %s: %w", devicePath, err)
+    }
+    return verifyChecksum(devicePath, hex.EncodeToString(hash.Sum(nil)), remote)
+}
+
+// createPartFile 在localPath所在目录创建写入中使用的临时文件
+// 与os.Create一样以0644减去umask作为权限，os.CreateTemp的0600会让重命名后的文件只有自己可读
+func createPartFile(localPath string) (*os.File, error) {
+    prefix := filepath.Join(filepath.Dir(localPath), "."+filepath.Base(localPath)+".")
+    for i := 0; i < 10000; i++ {
+        name := prefix + strconv.FormatUint(uint64(rand.Uint32()), 10) + ".part"
+        f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+        if os.IsExist(err) {
+            continue
+        }
+        return f, err
+    }
+    return nil, &os.PathError{Op: "createtemp", Path: prefix + "*.part", Err: os.ErrExist}
+}
+
+// 实现AndroidBot接口的PullFile方法
+func (b *androidBotImpl) PullFile(devicePath, localPath string) error {
+    devicePath, err := validateDevicePath(devicePath)
+    if err != nil {
+        return err
+    }
+    // 先写入同目录下的临时文件，校验通过后再重命名，避免留下不完整的文件
+    tmp, err := createPartFile(localPath)
+    if err != nil {
+        return fmt.Errorf("failed to create local file: %w", err)
+    }
+    defer os.Remove(tmp.Name())
+    if err := b.pullTo(devicePath, tmp); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return fmt.Errorf("failed to write local file: %w", err)
+    }
+    return os.Rename(tmp.Name(), localPath)
+}
+
+// 实现AndroidBot接口的ReadFile方法
+func (b *androidBotImpl) ReadFile(devicePath string) ([]byte, error) {
+    devicePath, err := validateDevicePath(devicePath)
+    if err != nil {
+        return nil, err
+    }
+    var buf bytes.Buffer
+    if err := b.pullTo(devicePath, &buf); err != nil {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// pullTo 将设备文件内容写入w，并校验SHA-256
+func (b *androidBotImpl) pullTo(devicePath string, w io.Writer) error {
+    r, remote, err := b.readDeviceFile(devicePath)
+    if err != nil {
+        return fmt.Errorf("failed to pull %s: %w", devicePath, err)
+    }
+    defer r.Close()
+    hash := sha256.New()
+    if _, err := io.Copy(io.MultiWriter(w, hash), r); err != nil {
+        return fmt.Errorf("failed to pull %s: %w", devicePath, err)
+    }
+    return verifyChecksum(devicePath, hex.EncodeToString(hash.Sum(nil)), remote)
+}
+
+// 实现AndroidBot接口的ListFiles方法
+func (b *androidBotImpl) ListFiles(dir string) ([]DeviceFile, error) {
+    if _, err := validateDevicePath(dir); err != nil {
+        return nil, err
+    }
+    // 实际实现将在后续添加
+    // 这里返回空切片和nil作为占位符
+    return []DeviceFile{}, nil
+}
+
+// 实现AndroidBot接口的DeleteFile方法
+func (b *androidBotImpl) DeleteFile(devicePath string) error {
+    devicePath, err := validateDevicePath(devicePath)
+    if err != nil {
+        return err
+    }
+    if devicePath == "/" {
+        return errors.New("androidbot: refusing to delete the root directory")
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的MakeDir方法
+func (b *androidBotImpl) MakeDir(devicePath string) error {
+    if _, err := validateDevicePath(devicePath); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的Screenshot方法
+// 截图先保存在设备上，拉取到内存后删除设备上的文件
+func (b *androidBotImpl) Screenshot() (image.Image, error) {
+    devicePath := fmt.Sprintf("%s/aibote_%d.png", screenshotDir, time.Now().UnixNano())
+    if err := b.TakeScreenshot(devicePath); err != nil {
+        return nil, err
+    }
+    data, err := b.ReadFile(devicePath)
+    if deleteErr := b.DeleteFile(devicePath); deleteErr != nil {
+        // 截图文件会被媒体库收录，不能静默留在设备上
+        return nil, errors.Join(err, fmt.Errorf("failed to delete screenshot %s: %w", devicePath, deleteErr))
+    }
+    if err != nil {
+        return nil, err
+    }
+    if len(data) == 0 {
+        return nil, ErrEmptyScreenshot
+    }
+    img, err := png.Decode(bytes.NewReader(data))
+    if err != nil {
+        return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+    }
+    return img, nil
+}
+
+// writeDeviceFile 将r的内容流式写入设备文件，返回设备端计算的SHA-256
+func (b *androidBotImpl) writeDeviceFile(devicePath string, size int64, r io.Reader) (string, error) {
+    // 实际实现将在后续添加
+    // 这里读完数据并返回所读数据的校验和作为占位符
+    hash := sha256.New()
+    if _, err := io.Copy(hash, r); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readDeviceFile 打开设备文件的读取流，同时返回设备端计算的SHA-256
+func (b *androidBotImpl) readDeviceFile(devicePath string) (io.ReadCloser, string, error) {
+    // 实际实现将在后续添加
+    // 这里返回空内容及其校验和作为占位符
+    empty := sha256.Sum256(nil)
+    return io.NopCloser(strings.NewReader("")), hex.EncodeToString(empty[:]), nil
+}
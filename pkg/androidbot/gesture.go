@@ -0,0 +1,195 @@
+package androidbot
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "time"
+)
+
+// 手势的限制和默认参数
+// Android无障碍服务的dispatchGesture最多支持10个同时触点，总时长不超过60秒
+const (
+    maxGesturePointers   = 10
+    maxGestureDuration   = 60 * time.Second
+    gestureSampleStep    = 16 * time.Millisecond
+    defaultSwipeDuration = 300 * time.Millisecond
+)
+
+// PathPoint 表示手指在某一时刻的位置
+// At: 相对于手势开始的时间
+type PathPoint struct {
+    X  int
+    Y  int
+    At time.Duration
+}
+
+// GesturePath 表示一根手指从按下到抬起的轨迹
+// 第一个点是按下的位置和时间，最后一个点是抬起的位置和时间
+// 相邻两点之间按直线匀速移动
+type GesturePath []PathPoint
+
+// Easing 是滑动的缓动函数
+// 参数t是0到1之间的时间进度，返回0到1之间的位置进度
+type Easing func(t float64) float64
+
+// 常用的缓动函数
+var (
+    // EaseLinear 匀速移动
+    EaseLinear Easing = func(t float64) float64 { return t }
+    // EaseInQuad 由慢到快
+    EaseInQuad Easing = func(t float64) float64 { return t * t }
+    // EaseOutQuad 由快到慢，接近手指快速滑动后减速的效果
+    EaseOutQuad Easing = func(t float64) float64 { return t * (2 - t) }
+    // EaseInOutQuad 两端慢中间快
+    EaseInOutQuad Easing = func(t float64) float64 {
+        if t < 0.5 {
+            return 2 * t * t
+        }
+        return -1 + (4-2*t)*t
+    }
+    // EaseInOutCubic 两端更慢中间更快，适合模拟人工拖动
+    EaseInOutCubic Easing = func(t float64) float64 {
+        if t < 0.5 {
+            return 4 * t * t * t
+        }
+        return 1 - math.Pow(-2*t+2, 3)/2
+    }
+)
+
+// validateGesture 检查手势轨迹是否有效
+func validateGesture(paths []GesturePath) error {
+    if len(paths) == 0 {
+        return errors.New("androidbot: gesture has no pointers")
+    }
+    if len(paths) > maxGesturePointers {
+        return fmt.Errorf("androidbot: gesture has %d pointers, at most %d are supported", len(paths), maxGesturePointers)
+    }
+    for i, path := range paths {
+        if len(path) == 0 {
+            return fmt.Errorf("androidbot: gesture pointer %d has no points", i)
+        }
+        for j, point := range path {
+            if point.At < 0 {
+                return fmt.Errorf("androidbot: gesture pointer %d point %d has negative time", i, j)
+            }
+            if j > 0 && point.At < path[j-1].At {
+                return fmt.Errorf("androidbot: gesture pointer %d point %d goes back in time", i, j)
+            }
+            if point.At > maxGestureDuration {
+                return fmt.Errorf("androidbot: gesture is longer than %s", maxGestureDuration)
+            }
+        }
+        // 系统要求每一笔的持续时间大于0，只有一个点或所有点时间相同的轨迹无法执行
+        if path[len(path)-1].At == path[0].At {
+            return fmt.Errorf("androidbot: gesture pointer %d has zero duration", i)
+        }
+    }
+    return nil
+}
+
+// easedPath 生成从(x1, y1)到(x2, y2)、按缓动函数移动的轨迹
+// 轨迹每gestureSampleStep采样一个点
+func easedPath(x1, y1, x2, y2 int, start, duration time.Duration, easing Easing) GesturePath {
+    if easing == nil {
+        easing = EaseLinear
+    }
+    steps := int(duration / gestureSampleStep)
+    if steps < 1 {
+        steps = 1
+    }
+    path := make(GesturePath, 0, steps+1)
+    for i := 0; i <= steps; i++ {
+        t := float64(i) / float64(steps)
+        p := easing(t)
+        path = append(path, PathPoint{
+            X:  x1 + int(math.Round(float64(x2-x1)*p)),
+            Y:  y1 + int(math.Round(float64(y2-y1)*p)),
+            At: start + time.Duration(float64(duration)*t),
+        })
+    }
+    return path
+}
+
+// arcPath 生成绕(cx, cy)移动的轨迹，半径和角度随时间线性变化
+// 角度单位为度，0度指向右方，顺时针为正(屏幕坐标系y轴向下)
+func arcPath(cx, cy int, fromRadius, toRadius, fromAngle, toAngle float64, duration time.Duration) GesturePath {
+    steps := int(duration / gestureSampleStep)
+    if steps < 1 {
+        steps = 1
+    }
+    path := make(GesturePath, 0, steps+1)
+    for i := 0; i <= steps; i++ {
+        t := float64(i) / float64(steps)
+        radius := fromRadius + (toRadius-fromRadius)*t
+        angle := (fromAngle + (toAngle-fromAngle)*t) * math.Pi / 180
+        path = append(path, PathPoint{
+            X:  cx + int(math.Round(radius*math.Cos(angle))),
+            Y:  cy + int(math.Round(radius*math.Sin(angle))),
+            At: time.Duration(float64(duration) * t),
+        })
+    }
+    return path
+}
+
+// 实现AndroidBot接口的LongPress方法
+func (b *androidBotImpl) LongPress(x, y int, duration time.Duration) error {
+    if duration <= 0 {
+        return errors.New("androidbot: long press duration must be positive")
+    }
+    return b.PerformGesture(GesturePath{{X: x, Y: y}, {X: x, Y: y, At: duration}})
+}
+
+// 实现AndroidBot接口的SwipeWithDuration方法
+func (b *androidBotImpl) SwipeWithDuration(x1, y1, x2, y2 int, duration time.Duration, easing Easing) error {
+    if duration <= 0 {
+        return errors.New("androidbot: swipe duration must be positive")
+    }
+    return b.PerformGesture(easedPath(x1, y1, x2, y2, 0, duration, easing))
+}
+
+// 实现AndroidBot接口的Pinch方法
+func (b *androidBotImpl) Pinch(cx, cy, fromRadius, toRadius int, duration time.Duration) error {
+    if fromRadius < 0 || toRadius < 0 {
+        return errors.New("androidbot: pinch radius must not be negative")
+    }
+    if duration <= 0 {
+        return errors.New("androidbot: pinch duration must be positive")
+    }
+    // 两根手指沿水平方向对称移动
+    return b.PerformGesture(
+        arcPath(cx, cy, float64(fromRadius), float64(toRadius), 0, 0, duration),
+        arcPath(cx, cy, float64(fromRadius), float64(toRadius), 180, 180, duration),
+    )
+}
+
+// 实现AndroidBot接口的Rotate方法
+func (b *androidBotImpl) Rotate(cx, cy, radius int, degrees float64, duration time.Duration) error {
+    if radius <= 0 {
+        return errors.New("androidbot: rotate radius must be positive")
+    }
+    if duration <= 0 {
+        return errors.New("androidbot: rotate duration must be positive")
+    }
+    // 两根手指位于直径两端，同时绕中心转动
+    return b.PerformGesture(
+        arcPath(cx, cy, float64(radius), float64(radius), 0, degrees, duration),
+        arcPath(cx, cy, float64(radius), float64(radius), 180, 180+degrees, duration),
+    )
+}
+
+// 实现AndroidBot接口的PerformGesture方法
+func (b *androidBotImpl) PerformGesture(paths ...GesturePath) error {
+    if err := validateGesture(paths); err != nil {
+        return err
+    }
+    return b.dispatchGesture(paths)
+}
+
+// dispatchGesture 将所有手指的轨迹作为一个手势发送给设备
+// 所有轨迹在同一个手势中执行，手指之间的时间关系由PathPoint.At保证
+func (b *androidBotImpl) dispatchGesture(paths []GesturePath) error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
@@ -0,0 +1,305 @@
+package androidbot
+
+import (
+    "bytes"
+    "encoding/xml"
+    "errors"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+)
+
+// Bounds 表示节点在屏幕上的矩形区域，单位为像素
+// Right和Bottom不包含在区域内，与uiautomator的bounds属性一致
+type Bounds struct {
+    Left   int
+    Top    int
+    Right  int
+    Bottom int
+}
+
+// Width 返回区域宽度
+func (r Bounds) Width() int {
+    return r.Right - r.Left
+}
+
+// Height 返回区域高度
+func (r Bounds) Height() int {
+    return r.Bottom - r.Top
+}
+
+// Center 返回区域中心点坐标
+func (r Bounds) Center() (int, int) {
+    return (r.Left + r.Right) / 2, (r.Top + r.Bottom) / 2
+}
+
+// Contains 判断坐标点是否在区域内
+func (r Bounds) Contains(x, y int) bool {
+    return x >= r.Left && x < r.Right && y >= r.Top && y < r.Bottom
+}
+
+// IsEmpty 判断区域是否为空，屏幕外或不可见的节点通常没有有效区域
+func (r Bounds) IsEmpty() bool {
+    return r.Width() <= 0 || r.Height() <= 0
+}
+
+// String 返回uiautomator格式的区域文本，如"[0,0][1080,2340]"
+func (r Bounds) String() string {
+    return fmt.Sprintf("[%d,%d][%d,%d]", r.Left, r.Top, r.Right, r.Bottom)
+}
+
+// ParseBounds 解析uiautomator格式的区域文本，如"[0,0][1080,2340]"
+func ParseBounds(s string) (Bounds, error) {
+    var r Bounds
+    if _, err := fmt.Sscanf(s, "[%d,%d][%d,%d]", &r.Left, &r.Top, &r.Right, &r.Bottom); err != nil {
+        return Bounds{}, fmt.Errorf("invalid bounds %q: %w", s, err)
+    }
+    return r, nil
+}
+
+// Node 表示界面层级中的一个节点
+// 字段对应uiautomator层级XML中的同名属性，Attrs保存节点的全部原始属性
+type Node struct {
+    Tag           string
+    Index         int
+    Text          string
+    ResourceID    string
+    Class         string
+    Package       string
+    ContentDesc   string
+    Checkable     bool
+    Checked       bool
+    Clickable     bool
+    Enabled       bool
+    Focusable     bool
+    Focused       bool
+    Scrollable    bool
+    LongClickable bool
+    Password      bool
+    Selected      bool
+    Bounds        Bounds
+    Attrs         map[string]string
+
+    Parent   *Node
+    Children []*Node
+}
+
+// Attr 返回节点的原始属性值
+func (n *Node) Attr(name string) (string, bool) {
+    value, ok := n.Attrs[name]
+    return value, ok
+}
+
+// Center 返回节点区域的中心点坐标
+func (n *Node) Center() (int, int) {
+    return n.Bounds.Center()
+}
+
+// Walk 按文档顺序遍历节点及其所有后代
+// fn返回false时停止遍历
+func (n *Node) Walk(fn func(*Node) bool) bool {
+    if !fn(n) {
+        return false
+    }
+    for _, child := range n.Children {
+        if !child.Walk(fn) {
+            return false
+        }
+    }
+    return true
+}
+
+// Filter 返回节点及其后代中所有满足条件的节点，按文档顺序排列
+func (n *Node) Filter(match func(*Node) bool) []*Node {
+    var result []*Node
+    n.Walk(func(node *Node) bool {
+        if match(node) {
+            result = append(result, node)
+        }
+        return true
+    })
+    return result
+}
+
+// FindAll 以当前节点为上下文执行XPath查询，返回所有匹配的节点
+// 以"/"或"//"开头的表达式从整个层级的根开始查找
+func (n *Node) FindAll(xpath string) ([]*Node, error) {
+    expr, err := compileXPath(xpath)
+    if err != nil {
+        return nil, err
+    }
+    return expr.selectNodes(n), nil
+}
+
+// Find 以当前节点为上下文执行XPath查询，返回第一个匹配的节点
+// 没有匹配的节点时返回ErrNodeNotFound
+func (n *Node) Find(xpath string) (*Node, error) {
+    nodes, err := n.FindAll(xpath)
+    if err != nil {
+        return nil, err
+    }
+    if len(nodes) == 0 {
+        return nil, &NodeNotFoundError{XPath: xpath}
+    }
+    return nodes[0], nil
+}
+
+// String 返回节点的简短描述，便于日志输出
+func (n *Node) String() string {
+    var b strings.Builder
+    b.WriteString(n.Class)
+    if n.Class == "" {
+        b.WriteString(n.Tag)
+    }
+    if n.ResourceID != "" {
+        fmt.Fprintf(&b, " id=%q", n.ResourceID)
+    }
+    if n.Text != "" {
+        fmt.Fprintf(&b, " text=%q", n.Text)
+    }
+    if n.ContentDesc != "" {
+        fmt.Fprintf(&b, " desc=%q", n.ContentDesc)
+    }
+    b.WriteString(" ")
+    b.WriteString(n.Bounds.String())
+    return b.String()
+}
+
+// ErrNodeNotFound 表示查询没有匹配的节点，可以通过errors.Is判断
+var ErrNodeNotFound = errors.New("androidbot: node not found")
+
+// NodeNotFoundError 表示XPath查询没有匹配的节点
+type NodeNotFoundError struct {
+    XPath string
+}
+
+func (e *NodeNotFoundError) Error() string {
+    return fmt.Sprintf("androidbot: no node matches %s", e.XPath)
+}
+
+func (e *NodeNotFoundError) Is(target error) bool {
+    return target == ErrNodeNotFound
+}
+
+// Hierarchy 表示一次界面层级转储的结果
+// 一次转储可以执行任意多次查询，不需要再与设备通信
+type Hierarchy struct {
+    // Root 是层级XML的根元素，通常为hierarchy
+    Root *Node
+    // Rotation 是转储时的屏幕旋转角度，取值0、1、2、3
+    Rotation int
+    // XML 是转储的原始XML
+    XML []byte
+}
+
+// Find 在整个层级中执行XPath查询，返回第一个匹配的节点
+// 相对表达式以根元素为上下文
+func (h *Hierarchy) Find(xpath string) (*Node, error) {
+    return h.Root.Find(xpath)
+}
+
+// FindAll 在整个层级中执行XPath查询，返回所有匹配的节点
+func (h *Hierarchy) FindAll(xpath string) ([]*Node, error) {
+    return h.Root.FindAll(xpath)
+}
+
+// Filter 返回层级中所有满足条件的节点
+func (h *Hierarchy) Filter(match func(*Node) bool) []*Node {
+    return h.Root.Filter(match)
+}
+
+// ParseHierarchy 解析uiautomator格式的界面层级XML
+func ParseHierarchy(data []byte) (*Hierarchy, error) {
+    decoder := xml.NewDecoder(bytes.NewReader(data))
+    // 部分设备输出的XML声明使用非UTF-8编码名，内容实际都是UTF-8
+    decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+        return input, nil
+    }
+
+    var root *Node
+    var stack []*Node
+    for {
+        token, err := decoder.Token()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, fmt.Errorf("failed to parse hierarchy: %w", err)
+        }
+        switch t := token.(type) {
+        case xml.StartElement:
+            node, err := newNode(t)
+            if err != nil {
+                return nil, err
+            }
+            if len(stack) == 0 {
+                if root != nil {
+                    return nil, errors.New("androidbot: hierarchy has multiple root elements")
+                }
+                root = node
+            } else {
+                parent := stack[len(stack)-1]
+                node.Parent = parent
+                parent.Children = append(parent.Children, node)
+            }
+            stack = append(stack, node)
+        case xml.EndElement:
+            stack = stack[:len(stack)-1]
+        }
+    }
+    if root == nil {
+        return nil, errors.New("androidbot: hierarchy is empty")
+    }
+    rotation, _ := strconv.Atoi(root.Attrs["rotation"])
+    return &Hierarchy{Root: root, Rotation: rotation, XML: data}, nil
+}
+
+// newNode 根据XML元素创建节点
+func newNode(element xml.StartElement) (*Node, error) {
+    node := &Node{Tag: element.Name.Local, Attrs: make(map[string]string, len(element.Attr))}
+    for _, attr := range element.Attr {
+        node.Attrs[attr.Name.Local] = attr.Value
+    }
+    a := node.Attrs
+    node.Index, _ = strconv.Atoi(a["index"])
+    node.Text = a["text"]
+    node.ResourceID = a["resource-id"]
+    node.Class = a["class"]
+    node.Package = a["package"]
+    node.ContentDesc = a["content-desc"]
+    node.Checkable = a["checkable"] == "true"
+    node.Checked = a["checked"] == "true"
+    node.Clickable = a["clickable"] == "true"
+    node.Enabled = a["enabled"] == "true"
+    node.Focusable = a["focusable"] == "true"
+    node.Focused = a["focused"] == "true"
+    node.Scrollable = a["scrollable"] == "true"
+    node.LongClickable = a["long-clickable"] == "true"
+    node.Password = a["password"] == "true"
+    node.Selected = a["selected"] == "true"
+    if bounds, ok := a["bounds"]; ok && bounds != "" {
+        r, err := ParseBounds(bounds)
+        if err != nil {
+            return nil, err
+        }
+        node.Bounds = r
+    }
+    return node, nil
+}
+
+// fetchHierarchy 从设备获取界面层级XML
+func (b *androidBotImpl) fetchHierarchy() ([]byte, error) {
+    // 实际实现将在后续添加
+    // 这里返回空的层级作为占位符
+    return []byte(`<?xml version="1.0" encoding="UTF-8"?><hierarchy rotation="0"></hierarchy>`), nil
+}
+
+// 实现AndroidBot接口的DumpHierarchy方法
+func (b *androidBotImpl) DumpHierarchy() (*Hierarchy, error) {
+    data, err := b.fetchHierarchy()
+    if err != nil {
+        return nil, fmt.Errorf("failed to dump hierarchy: %w", err)
+    }
+    return ParseHierarchy(data)
+}
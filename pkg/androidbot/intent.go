@@ -0,0 +1,233 @@
+package androidbot
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "net/url"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// 常用的Intent动作
+const (
+    ActionMain = "android.intent.action.MAIN"
+    ActionView = "android.intent.action.VIEW"
+    ActionSend = "android.intent.action.SEND"
+    ActionDial = "android.intent.action.DIAL"
+    ActionEdit = "android.intent.action.EDIT"
+)
+
+// 常用的Intent类别
+const (
+    CategoryDefault   = "android.intent.category.DEFAULT"
+    CategoryLauncher  = "android.intent.category.LAUNCHER"
+    CategoryBrowsable = "android.intent.category.BROWSABLE"
+    CategoryHome      = "android.intent.category.HOME"
+)
+
+// IntentFlag 表示Intent的标志位，可以用|组合
+type IntentFlag int
+
+const (
+    FlagIncludeStoppedPackages IntentFlag = 0x00000020 // 广播也发送给已停止的应用
+    FlagActivityClearTask      IntentFlag = 0x00008000 // 启动前清空任务栈
+    FlagActivityNoAnimation    IntentFlag = 0x00010000 // 不显示切换动画
+    FlagActivityReorderToFront IntentFlag = 0x00020000 // 已存在的Activity移到栈顶
+    FlagActivityExcludeRecents IntentFlag = 0x00800000 // 不出现在最近任务中
+    FlagActivityClearTop       IntentFlag = 0x04000000 // 清除目标Activity之上的Activity
+    FlagActivityNewTask        IntentFlag = 0x10000000 // 在新任务中启动
+    FlagActivitySingleTop      IntentFlag = 0x20000000 // 目标已在栈顶时不重新创建
+    FlagActivityNoHistory      IntentFlag = 0x40000000 // 离开后不保留在任务栈中
+)
+
+// Intent 表示一个Android Intent
+// Component: 明确指定的组件，格式为"包名/类名"，类名可以写成".MainActivity"的简写
+// Package: 限定接收Intent的应用，Component为空时用于隐式Intent
+// Extras: 附加数据，支持string、bool、int、int32、int64、float32、float64、*url.URL、
+// []string、[]int、[]int64、[]float32类型的值，int和[]int按Java int发送，超出32位范围时返回错误
+type Intent struct {
+    Action     string
+    Data       string
+    MimeType   string
+    Component  string
+    Package    string
+    Categories []string
+    Flags      IntentFlag
+    Extras     map[string]interface{}
+}
+
+// validate 检查Intent是否有效
+func (i Intent) validate() error {
+    if i.Action == "" && i.Component == "" && i.Data == "" {
+        return errors.New("androidbot: intent needs an action, component or data")
+    }
+    if i.Component != "" {
+        pkg, class, ok := strings.Cut(i.Component, "/")
+        if !ok || class == "" {
+            return fmt.Errorf("androidbot: invalid component %q, expected package/class", i.Component)
+        }
+        if err := validatePackageName(pkg); err != nil {
+            return err
+        }
+    }
+    if i.Package != "" {
+        if err := validatePackageName(i.Package); err != nil {
+            return err
+        }
+    }
+    if i.Data != "" {
+        if _, err := url.Parse(i.Data); err != nil {
+            return fmt.Errorf("androidbot: invalid intent data uri: %w", err)
+        }
+    }
+    for key, value := range i.Extras {
+        if key == "" {
+            return errors.New("androidbot: intent extra key is empty")
+        }
+        if _, _, err := extraArg(value); err != nil {
+            return fmt.Errorf("androidbot: intent extra %q: %w", key, err)
+        }
+    }
+    return nil
+}
+
+// Args 返回与am start、am broadcast命令相同格式的参数列表
+// 例如：[-a android.intent.action.VIEW -d https://example.com -f 0x10000000]
+// Extras按键名排序，保证输出稳定
+func (i Intent) Args() ([]string, error) {
+    if err := i.validate(); err != nil {
+        return nil, err
+    }
+    var args []string
+    if i.Action != "" {
+        args = append(args, "-a", i.Action)
+    }
+    if i.Data != "" {
+        args = append(args, "-d", i.Data)
+    }
+    if i.MimeType != "" {
+        args = append(args, "-t", i.MimeType)
+    }
+    for _, category := range i.Categories {
+        args = append(args, "-c", category)
+    }
+    if i.Component != "" {
+        args = append(args, "-n", i.Component)
+    }
+    if i.Flags != 0 {
+        args = append(args, "-f", fmt.Sprintf("0x%08x", int(i.Flags)))
+    }
+    keys := make([]string, 0, len(i.Extras))
+    for key := range i.Extras {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    for _, key := range keys {
+        flag, value, _ := extraArg(i.Extras[key])
+        args = append(args, flag, key, value)
+    }
+    // 包名必须是最后一个参数
+    if i.Package != "" && i.Component == "" {
+        args = append(args, i.Package)
+    }
+    return args, nil
+}
+
+// extraArg 返回附加数据对应的am参数和值文本
+func extraArg(value interface{}) (string, string, error) {
+    switch v := value.(type) {
+    case string:
+        return "--es", v, nil
+    case bool:
+        return "--ez", strconv.FormatBool(v), nil
+    case int:
+        // Go的int是64位，超出Java int范围的值会被am拒绝或截断，需要显式使用int64
+        if v < math.MinInt32 || v > math.MaxInt32 {
+            return "", "", fmt.Errorf("int extra %d overflows a Java int, use int64", v)
+        }
+        return "--ei", strconv.Itoa(v), nil
+    case int32:
+        return "--ei", strconv.FormatInt(int64(v), 10), nil
+    case int64:
+        return "--el", strconv.FormatInt(v, 10), nil
+    case float32:
+        return "--ef", strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+    case float64:
+        return "--ed", strconv.FormatFloat(v, 'g', -1, 64), nil
+    case *url.URL:
+        return "--eu", v.String(), nil
+    case []string:
+        // am以逗号分隔数组元素，元素中的逗号需要转义
+        escaped := make([]string, len(v))
+        for i, s := range v {
+            escaped[i] = strings.ReplaceAll(s, ",", `\,`)
+        }
+        return "--esa", strings.Join(escaped, ","), nil
+    case []int:
+        for _, n := range v {
+            if n < math.MinInt32 || n > math.MaxInt32 {
+                return "", "", fmt.Errorf("int array extra element %d overflows a Java int, use []int64", n)
+            }
+        }
+        return "--eia", joinNumbers(v, func(n int) string { return strconv.Itoa(n) }), nil
+    case []int64:
+        return "--ela", joinNumbers(v, func(n int64) string { return strconv.FormatInt(n, 10) }), nil
+    case []float32:
+        return "--efa", joinNumbers(v, func(n float32) string { return strconv.FormatFloat(float64(n), 'g', -1, 32) }), nil
+    }
+    return "", "", fmt.Errorf("unsupported extra type %T", value)
+}
+
+// joinNumbers 以逗号连接数组元素
+func joinNumbers[T any](values []T, format func(T) string) string {
+    parts := make([]string, len(values))
+    for i, v := range values {
+        parts[i] = format(v)
+    }
+    return strings.Join(parts, ",")
+}
+
+// 实现AndroidBot接口的StartActivity方法
+func (b *androidBotImpl) StartActivity(intent Intent) error {
+    args, err := intent.Args()
+    if err != nil {
+        return err
+    }
+    return b.sendIntent("start", args)
+}
+
+// 实现AndroidBot接口的OpenDeepLink方法
+func (b *androidBotImpl) OpenDeepLink(uri string, packageName string) error {
+    if uri == "" {
+        return errors.New("androidbot: deep link uri is required")
+    }
+    return b.StartActivity(Intent{
+        Action:     ActionView,
+        Data:       uri,
+        Package:    packageName,
+        Categories: []string{CategoryBrowsable},
+        Flags:      FlagActivityNewTask,
+    })
+}
+
+// 实现AndroidBot接口的SendBroadcast方法
+func (b *androidBotImpl) SendBroadcast(intent Intent) error {
+    if intent.Action == "" && intent.Component == "" {
+        return errors.New("androidbot: broadcast intent needs an action or component")
+    }
+    args, err := intent.Args()
+    if err != nil {
+        return err
+    }
+    return b.sendIntent("broadcast", args)
+}
+
+// sendIntent 让设备按am命令的参数启动Activity或发送广播
+// kind: "start"或"broadcast"
+func (b *androidBotImpl) sendIntent(kind string, args []string) error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
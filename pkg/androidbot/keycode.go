@@ -0,0 +1,780 @@
+package androidbot
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// KeyCode 表示Android按键码，取值与android.view.KeyEvent中的KEYCODE_*常量一致
+type KeyCode int
+
+// Android按键码
+const (
+    KeyCodeUnknown                   KeyCode = 0
+    KeyCodeSoftLeft                  KeyCode = 1
+    KeyCodeSoftRight                 KeyCode = 2
+    KeyCodeHome                      KeyCode = 3
+    KeyCodeBack                      KeyCode = 4
+    KeyCodeCall                      KeyCode = 5
+    KeyCodeEndcall                   KeyCode = 6
+    KeyCode0                         KeyCode = 7
+    KeyCode1                         KeyCode = 8
+    KeyCode2                         KeyCode = 9
+    KeyCode3                         KeyCode = 10
+    KeyCode4                         KeyCode = 11
+    KeyCode5                         KeyCode = 12
+    KeyCode6                         KeyCode = 13
+    KeyCode7                         KeyCode = 14
+    KeyCode8                         KeyCode = 15
+    KeyCode9                         KeyCode = 16
+    KeyCodeStar                      KeyCode = 17
+    KeyCodePound                     KeyCode = 18
+    KeyCodeDpadUp                    KeyCode = 19
+    KeyCodeDpadDown                  KeyCode = 20
+    KeyCodeDpadLeft                  KeyCode = 21
+    KeyCodeDpadRight                 KeyCode = 22
+    KeyCodeDpadCenter                KeyCode = 23
+    KeyCodeVolumeUp                  KeyCode = 24
+    KeyCodeVolumeDown                KeyCode = 25
+    KeyCodePower                     KeyCode = 26
+    KeyCodeCamera                    KeyCode = 27
+    KeyCodeClear                     KeyCode = 28
+    KeyCodeA                         KeyCode = 29
+    KeyCodeB                         KeyCode = 30
+    KeyCodeC                         KeyCode = 31
+    KeyCodeD                         KeyCode = 32
+    KeyCodeE                         KeyCode = 33
+    KeyCodeF                         KeyCode = 34
+    KeyCodeG                         KeyCode = 35
+    KeyCodeH                         KeyCode = 36
+    KeyCodeI                         KeyCode = 37
+    KeyCodeJ                         KeyCode = 38
+    KeyCodeK                         KeyCode = 39
+    KeyCodeL                         KeyCode = 40
+    KeyCodeM                         KeyCode = 41
+    KeyCodeN                         KeyCode = 42
+    KeyCodeO                         KeyCode = 43
+    KeyCodeP                         KeyCode = 44
+    KeyCodeQ                         KeyCode = 45
+    KeyCodeR                         KeyCode = 46
+    KeyCodeS                         KeyCode = 47
+    KeyCodeT                         KeyCode = 48
+    KeyCodeU                         KeyCode = 49
+    KeyCodeV                         KeyCode = 50
+    KeyCodeW                         KeyCode = 51
+    KeyCodeX                         KeyCode = 52
+    KeyCodeY                         KeyCode = 53
+    KeyCodeZ                         KeyCode = 54
+    KeyCodeComma                     KeyCode = 55
+    KeyCodePeriod                    KeyCode = 56
+    KeyCodeAltLeft                   KeyCode = 57
+    KeyCodeAltRight                  KeyCode = 58
+    KeyCodeShiftLeft                 KeyCode = 59
+    KeyCodeShiftRight                KeyCode = 60
+    KeyCodeTab                       KeyCode = 61
+    KeyCodeSpace                     KeyCode = 62
+    KeyCodeSym                       KeyCode = 63
+    KeyCodeExplorer                  KeyCode = 64
+    KeyCodeEnvelope                  KeyCode = 65
+    KeyCodeEnter                     KeyCode = 66
+    KeyCodeDel                       KeyCode = 67
+    KeyCodeGrave                     KeyCode = 68
+    KeyCodeMinus                     KeyCode = 69
+    KeyCodeEquals                    KeyCode = 70
+    KeyCodeLeftBracket               KeyCode = 71
+    KeyCodeRightBracket              KeyCode = 72
+    KeyCodeBackslash                 KeyCode = 73
+    KeyCodeSemicolon                 KeyCode = 74
+    KeyCodeApostrophe                KeyCode = 75
+    KeyCodeSlash                     KeyCode = 76
+    KeyCodeAt                        KeyCode = 77
+    KeyCodeNum                       KeyCode = 78
+    KeyCodeHeadsethook               KeyCode = 79
+    KeyCodeFocus                     KeyCode = 80
+    KeyCodePlus                      KeyCode = 81
+    KeyCodeMenu                      KeyCode = 82
+    KeyCodeNotification              KeyCode = 83
+    KeyCodeSearch                    KeyCode = 84
+    KeyCodeMediaPlayPause            KeyCode = 85
+    KeyCodeMediaStop                 KeyCode = 86
+    KeyCodeMediaNext                 KeyCode = 87
+    KeyCodeMediaPrevious             KeyCode = 88
+    KeyCodeMediaRewind               KeyCode = 89
+    KeyCodeMediaFastForward          KeyCode = 90
+    KeyCodeMute                      KeyCode = 91
+    KeyCodePageUp                    KeyCode = 92
+    KeyCodePageDown                  KeyCode = 93
+    KeyCodePictsymbols               KeyCode = 94
+    KeyCodeSwitchCharset             KeyCode = 95
+    KeyCodeButtonA                   KeyCode = 96
+    KeyCodeButtonB                   KeyCode = 97
+    KeyCodeButtonC                   KeyCode = 98
+    KeyCodeButtonX                   KeyCode = 99
+    KeyCodeButtonY                   KeyCode = 100
+    KeyCodeButtonZ                   KeyCode = 101
+    KeyCodeButtonL1                  KeyCode = 102
+    KeyCodeButtonR1                  KeyCode = 103
+    KeyCodeButtonL2                  KeyCode = 104
+    KeyCodeButtonR2                  KeyCode = 105
+    KeyCodeButtonThumbl              KeyCode = 106
+    KeyCodeButtonThumbr              KeyCode = 107
+    KeyCodeButtonStart               KeyCode = 108
+    KeyCodeButtonSelect              KeyCode = 109
+    KeyCodeButtonMode                KeyCode = 110
+    KeyCodeEscape                    KeyCode = 111
+    KeyCodeForwardDel                KeyCode = 112
+    KeyCodeCtrlLeft                  KeyCode = 113
+    KeyCodeCtrlRight                 KeyCode = 114
+    KeyCodeCapsLock                  KeyCode = 115
+    KeyCodeScrollLock                KeyCode = 116
+    KeyCodeMetaLeft                  KeyCode = 117
+    KeyCodeMetaRight                 KeyCode = 118
+    KeyCodeFunction                  KeyCode = 119
+    KeyCodeSysrq                     KeyCode = 120
+    KeyCodeBreak                     KeyCode = 121
+    KeyCodeMoveHome                  KeyCode = 122
+    KeyCodeMoveEnd                   KeyCode = 123
+    KeyCodeInsert                    KeyCode = 124
+    KeyCodeForward                   KeyCode = 125
+    KeyCodeMediaPlay                 KeyCode = 126
+    KeyCodeMediaPause                KeyCode = 127
+    KeyCodeMediaClose                KeyCode = 128
+    KeyCodeMediaEject                KeyCode = 129
+    KeyCodeMediaRecord               KeyCode = 130
+    KeyCodeF1                        KeyCode = 131
+    KeyCodeF2                        KeyCode = 132
+    KeyCodeF3                        KeyCode = 133
+    KeyCodeF4                        KeyCode = 134
+    KeyCodeF5                        KeyCode = 135
+    KeyCodeF6                        KeyCode = 136
+    KeyCodeF7                        KeyCode = 137
+    KeyCodeF8                        KeyCode = 138
+    KeyCodeF9                        KeyCode = 139
+    KeyCodeF10                       KeyCode = 140
+    KeyCodeF11                       KeyCode = 141
+    KeyCodeF12                       KeyCode = 142
+    KeyCodeNumLock                   KeyCode = 143
+    KeyCodeNumpad0                   KeyCode = 144
+    KeyCodeNumpad1                   KeyCode = 145
+    KeyCodeNumpad2                   KeyCode = 146
+    KeyCodeNumpad3                   KeyCode = 147
+    KeyCodeNumpad4                   KeyCode = 148
+    KeyCodeNumpad5                   KeyCode = 149
+    KeyCodeNumpad6                   KeyCode = 150
+    KeyCodeNumpad7                   KeyCode = 151
+    KeyCodeNumpad8                   KeyCode = 152
+    KeyCodeNumpad9                   KeyCode = 153
+    KeyCodeNumpadDivide              KeyCode = 154
+    KeyCodeNumpadMultiply            KeyCode = 155
+    KeyCodeNumpadSubtract            KeyCode = 156
+    KeyCodeNumpadAdd                 KeyCode = 157
+    KeyCodeNumpadDot                 KeyCode = 158
+    KeyCodeNumpadComma               KeyCode = 159
+    KeyCodeNumpadEnter               KeyCode = 160
+    KeyCodeNumpadEquals              KeyCode = 161
+    KeyCodeNumpadLeftParen           KeyCode = 162
+    KeyCodeNumpadRightParen          KeyCode = 163
+    KeyCodeVolumeMute                KeyCode = 164
+    KeyCodeInfo                      KeyCode = 165
+    KeyCodeChannelUp                 KeyCode = 166
+    KeyCodeChannelDown               KeyCode = 167
+    KeyCodeZoomIn                    KeyCode = 168
+    KeyCodeZoomOut                   KeyCode = 169
+    KeyCodeTv                        KeyCode = 170
+    KeyCodeWindow                    KeyCode = 171
+    KeyCodeGuide                     KeyCode = 172
+    KeyCodeDvr                       KeyCode = 173
+    KeyCodeBookmark                  KeyCode = 174
+    KeyCodeCaptions                  KeyCode = 175
+    KeyCodeSettings                  KeyCode = 176
+    KeyCodeTvPower                   KeyCode = 177
+    KeyCodeTvInput                   KeyCode = 178
+    KeyCodeStbPower                  KeyCode = 179
+    KeyCodeStbInput                  KeyCode = 180
+    KeyCodeAvrPower                  KeyCode = 181
+    KeyCodeAvrInput                  KeyCode = 182
+    KeyCodeProgRed                   KeyCode = 183
+    KeyCodeProgGreen                 KeyCode = 184
+    KeyCodeProgYellow                KeyCode = 185
+    KeyCodeProgBlue                  KeyCode = 186
+    KeyCodeAppSwitch                 KeyCode = 187
+    KeyCodeButton1                   KeyCode = 188
+    KeyCodeButton2                   KeyCode = 189
+    KeyCodeButton3                   KeyCode = 190
+    KeyCodeButton4                   KeyCode = 191
+    KeyCodeButton5                   KeyCode = 192
+    KeyCodeButton6                   KeyCode = 193
+    KeyCodeButton7                   KeyCode = 194
+    KeyCodeButton8                   KeyCode = 195
+    KeyCodeButton9                   KeyCode = 196
+    KeyCodeButton10                  KeyCode = 197
+    KeyCodeButton11                  KeyCode = 198
+    KeyCodeButton12                  KeyCode = 199
+    KeyCodeButton13                  KeyCode = 200
+    KeyCodeButton14                  KeyCode = 201
+    KeyCodeButton15                  KeyCode = 202
+    KeyCodeButton16                  KeyCode = 203
+    KeyCodeLanguageSwitch            KeyCode = 204
+    KeyCodeMannerMode                KeyCode = 205
+    KeyCode3DMode                    KeyCode = 206
+    KeyCodeContacts                  KeyCode = 207
+    KeyCodeCalendar                  KeyCode = 208
+    KeyCodeMusic                     KeyCode = 209
+    KeyCodeCalculator                KeyCode = 210
+    KeyCodeZenkakuHankaku            KeyCode = 211
+    KeyCodeEisu                      KeyCode = 212
+    KeyCodeMuhenkan                  KeyCode = 213
+    KeyCodeHenkan                    KeyCode = 214
+    KeyCodeKatakanaHiragana          KeyCode = 215
+    KeyCodeYen                       KeyCode = 216
+    KeyCodeRo                        KeyCode = 217
+    KeyCodeKana                      KeyCode = 218
+    KeyCodeAssist                    KeyCode = 219
+    KeyCodeBrightnessDown            KeyCode = 220
+    KeyCodeBrightnessUp              KeyCode = 221
+    KeyCodeMediaAudioTrack           KeyCode = 222
+    KeyCodeSleep                     KeyCode = 223
+    KeyCodeWakeup                    KeyCode = 224
+    KeyCodePairing                   KeyCode = 225
+    KeyCodeMediaTopMenu              KeyCode = 226
+    KeyCode11                        KeyCode = 227
+    KeyCode12                        KeyCode = 228
+    KeyCodeLastChannel               KeyCode = 229
+    KeyCodeTvDataService             KeyCode = 230
+    KeyCodeVoiceAssist               KeyCode = 231
+    KeyCodeTvRadioService            KeyCode = 232
+    KeyCodeTvTeletext                KeyCode = 233
+    KeyCodeTvNumberEntry             KeyCode = 234
+    KeyCodeTvTerrestrialAnalog       KeyCode = 235
+    KeyCodeTvTerrestrialDigital      KeyCode = 236
+    KeyCodeTvSatellite               KeyCode = 237
+    KeyCodeTvSatelliteBs             KeyCode = 238
+    KeyCodeTvSatelliteCs             KeyCode = 239
+    KeyCodeTvSatelliteService        KeyCode = 240
+    KeyCodeTvNetwork                 KeyCode = 241
+    KeyCodeTvAntennaCable            KeyCode = 242
+    KeyCodeTvInputHdmi1              KeyCode = 243
+    KeyCodeTvInputHdmi2              KeyCode = 244
+    KeyCodeTvInputHdmi3              KeyCode = 245
+    KeyCodeTvInputHdmi4              KeyCode = 246
+    KeyCodeTvInputComposite1         KeyCode = 247
+    KeyCodeTvInputComposite2         KeyCode = 248
+    KeyCodeTvInputComponent1         KeyCode = 249
+    KeyCodeTvInputComponent2         KeyCode = 250
+    KeyCodeTvInputVga1               KeyCode = 251
+    KeyCodeTvAudioDescription        KeyCode = 252
+    KeyCodeTvAudioDescriptionMixUp   KeyCode = 253
+    KeyCodeTvAudioDescriptionMixDown KeyCode = 254
+    KeyCodeTvZoomMode                KeyCode = 255
+    KeyCodeTvContentsMenu            KeyCode = 256
+    KeyCodeTvMediaContextMenu        KeyCode = 257
+    KeyCodeTvTimerProgramming        KeyCode = 258
+    KeyCodeHelp                      KeyCode = 259
+    KeyCodeNavigatePrevious          KeyCode = 260
+    KeyCodeNavigateNext              KeyCode = 261
+    KeyCodeNavigateIn                KeyCode = 262
+    KeyCodeNavigateOut               KeyCode = 263
+    KeyCodeStemPrimary               KeyCode = 264
+    KeyCodeStem1                     KeyCode = 265
+    KeyCodeStem2                     KeyCode = 266
+    KeyCodeStem3                     KeyCode = 267
+    KeyCodeDpadUpLeft                KeyCode = 268
+    KeyCodeDpadDownLeft              KeyCode = 269
+    KeyCodeDpadUpRight               KeyCode = 270
+    KeyCodeDpadDownRight             KeyCode = 271
+    KeyCodeMediaSkipForward          KeyCode = 272
+    KeyCodeMediaSkipBackward         KeyCode = 273
+    KeyCodeMediaStepForward          KeyCode = 274
+    KeyCodeMediaStepBackward         KeyCode = 275
+    KeyCodeSoftSleep                 KeyCode = 276
+    KeyCodeCut                       KeyCode = 277
+    KeyCodeCopy                      KeyCode = 278
+    KeyCodePaste                     KeyCode = 279
+    KeyCodeSystemNavigationUp        KeyCode = 280
+    KeyCodeSystemNavigationDown      KeyCode = 281
+    KeyCodeSystemNavigationLeft      KeyCode = 282
+    KeyCodeSystemNavigationRight     KeyCode = 283
+    KeyCodeAllApps                   KeyCode = 284
+    KeyCodeRefresh                   KeyCode = 285
+    KeyCodeThumbsUp                  KeyCode = 286
+    KeyCodeThumbsDown                KeyCode = 287
+    KeyCodeProfileSwitch             KeyCode = 288
+    KeyCodeVideoApp1                 KeyCode = 289
+    KeyCodeVideoApp2                 KeyCode = 290
+    KeyCodeVideoApp3                 KeyCode = 291
+    KeyCodeVideoApp4                 KeyCode = 292
+    KeyCodeVideoApp5                 KeyCode = 293
+    KeyCodeVideoApp6                 KeyCode = 294
+    KeyCodeVideoApp7                 KeyCode = 295
+    KeyCodeVideoApp8                 KeyCode = 296
+    KeyCodeFeaturedApp1              KeyCode = 297
+    KeyCodeFeaturedApp2              KeyCode = 298
+    KeyCodeFeaturedApp3              KeyCode = 299
+    KeyCodeFeaturedApp4              KeyCode = 300
+    KeyCodeDemoApp1                  KeyCode = 301
+    KeyCodeDemoApp2                  KeyCode = 302
+    KeyCodeDemoApp3                  KeyCode = 303
+    KeyCodeDemoApp4                  KeyCode = 304
+    KeyCodeKeyboardBacklightDown     KeyCode = 305
+    KeyCodeKeyboardBacklightUp       KeyCode = 306
+    KeyCodeKeyboardBacklightToggle   KeyCode = 307
+    KeyCodeStylusButtonPrimary       KeyCode = 308
+    KeyCodeStylusButtonSecondary     KeyCode = 309
+    KeyCodeStylusButtonTertiary      KeyCode = 310
+    KeyCodeStylusButtonTail          KeyCode = 311
+    KeyCodeRecentApps                KeyCode = 312
+    KeyCodeMacro1                    KeyCode = 313
+    KeyCodeMacro2                    KeyCode = 314
+    KeyCodeMacro3                    KeyCode = 315
+    KeyCodeMacro4                    KeyCode = 316
+)
+
+// keyCodeNames 是按键码对应的KEYCODE_*名称(去掉前缀)
+var keyCodeNames = map[KeyCode]string{
+    KeyCodeUnknown:                   "UNKNOWN",
+    KeyCodeSoftLeft:                  "SOFT_LEFT",
+    KeyCodeSoftRight:                 "SOFT_RIGHT",
+    KeyCodeHome:                      "HOME",
+    KeyCodeBack:                      "BACK",
+    KeyCodeCall:                      "CALL",
+    KeyCodeEndcall:                   "ENDCALL",
+    KeyCode0:                         "0",
+    KeyCode1:                         "1",
+    KeyCode2:                         "2",
+    KeyCode3:                         "3",
+    KeyCode4:                         "4",
+    KeyCode5:                         "5",
+    KeyCode6:                         "6",
+    KeyCode7:                         "7",
+    KeyCode8:                         "8",
+    KeyCode9:                         "9",
+    KeyCodeStar:                      "STAR",
+    KeyCodePound:                     "POUND",
+    KeyCodeDpadUp:                    "DPAD_UP",
+    KeyCodeDpadDown:                  "DPAD_DOWN",
+    KeyCodeDpadLeft:                  "DPAD_LEFT",
+    KeyCodeDpadRight:                 "DPAD_RIGHT",
+    KeyCodeDpadCenter:                "DPAD_CENTER",
+    KeyCodeVolumeUp:                  "VOLUME_UP",
+    KeyCodeVolumeDown:                "VOLUME_DOWN",
+    KeyCodePower:                     "POWER",
+    KeyCodeCamera:                    "CAMERA",
+    KeyCodeClear:                     "CLEAR",
+    KeyCodeA:                         "A",
+    KeyCodeB:                         "B",
+    KeyCodeC:                         "C",
+    KeyCodeD:                         "D",
+    KeyCodeE:                         "E",
+    KeyCodeF:                         "F",
+    KeyCodeG:                         "G",
+    KeyCodeH:                         "H",
+    KeyCodeI:                         "I",
+    KeyCodeJ:                         "J",
+    KeyCodeK:                         "K",
+    KeyCodeL:                         "L",
+    KeyCodeM:                         "M",
+    KeyCodeN:                         "N",
+    KeyCodeO:                         "O",
+    KeyCodeP:                         "P",
+    KeyCodeQ:                         "Q",
+    KeyCodeR:                         "R",
+    KeyCodeS:                         "S",
+    KeyCodeT:                         "T",
+    KeyCodeU:                         "U",
+    KeyCodeV:                         "V",
+    KeyCodeW:                         "W",
+    KeyCodeX:                         "X",
+    KeyCodeY:                         "Y",
+    KeyCodeZ:                         "Z",
+    KeyCodeComma:                     "COMMA",
+    KeyCodePeriod:                    "PERIOD",
+    KeyCodeAltLeft:                   "ALT_LEFT",
+    KeyCodeAltRight:                  "ALT_RIGHT",
+    KeyCodeShiftLeft:                 "SHIFT_LEFT",
+    KeyCodeShiftRight:                "SHIFT_RIGHT",
+    KeyCodeTab:                       "TAB",
+    KeyCodeSpace:                     "SPACE",
+    KeyCodeSym:                       "SYM",
+    KeyCodeExplorer:                  "EXPLORER",
+    KeyCodeEnvelope:                  "ENVELOPE",
+    KeyCodeEnter:                     "ENTER",
+    KeyCodeDel:                       "DEL",
+    KeyCodeGrave:                     "GRAVE",
+    KeyCodeMinus:                     "MINUS",
+    KeyCodeEquals:                    "EQUALS",
+    KeyCodeLeftBracket:               "LEFT_BRACKET",
+    KeyCodeRightBracket:              "RIGHT_BRACKET",
+    KeyCodeBackslash:                 "BACKSLASH",
+    KeyCodeSemicolon:                 "SEMICOLON",
+    KeyCodeApostrophe:                "APOSTROPHE",
+    KeyCodeSlash:                     "SLASH",
+    KeyCodeAt:                        "AT",
+    KeyCodeNum:                       "NUM",
+    KeyCodeHeadsethook:               "HEADSETHOOK",
+    KeyCodeFocus:                     "FOCUS",
+    KeyCodePlus:                      "PLUS",
+    KeyCodeMenu:                      "MENU",
+    KeyCodeNotification:              "NOTIFICATION",
+    KeyCodeSearch:                    "SEARCH",
+    KeyCodeMediaPlayPause:            "MEDIA_PLAY_PAUSE",
+    KeyCodeMediaStop:                 "MEDIA_STOP",
+    KeyCodeMediaNext:                 "MEDIA_NEXT",
+    KeyCodeMediaPrevious:             "MEDIA_PREVIOUS",
+    KeyCodeMediaRewind:               "MEDIA_REWIND",
+    KeyCodeMediaFastForward:          "MEDIA_FAST_FORWARD",
+    KeyCodeMute:                      "MUTE",
+    KeyCodePageUp:                    "PAGE_UP",
+    KeyCodePageDown:                  "PAGE_DOWN",
+    KeyCodePictsymbols:               "PICTSYMBOLS",
+    KeyCodeSwitchCharset:             "SWITCH_CHARSET",
+    KeyCodeButtonA:                   "BUTTON_A",
+    KeyCodeButtonB:                   "BUTTON_B",
+    KeyCodeButtonC:                   "BUTTON_C",
+    KeyCodeButtonX:                   "BUTTON_X",
+    KeyCodeButtonY:                   "BUTTON_Y",
+    KeyCodeButtonZ:                   "BUTTON_Z",
+    KeyCodeButtonL1:                  "BUTTON_L1",
+    KeyCodeButtonR1:                  "BUTTON_R1",
+    KeyCodeButtonL2:                  "BUTTON_L2",
+    KeyCodeButtonR2:                  "BUTTON_R2",
+    KeyCodeButtonThumbl:              "BUTTON_THUMBL",
+    KeyCodeButtonThumbr:              "BUTTON_THUMBR",
+    KeyCodeButtonStart:               "BUTTON_START",
+    KeyCodeButtonSelect:              "BUTTON_SELECT",
+    KeyCodeButtonMode:                "BUTTON_MODE",
+    KeyCodeEscape:                    "ESCAPE",
+    KeyCodeForwardDel:                "FORWARD_DEL",
+    KeyCodeCtrlLeft:                  "CTRL_LEFT",
+    KeyCodeCtrlRight:                 "CTRL_RIGHT",
+    KeyCodeCapsLock:                  "CAPS_LOCK",
+    KeyCodeScrollLock:                "SCROLL_LOCK",
+    KeyCodeMetaLeft:                  "META_LEFT",
+    KeyCodeMetaRight:                 "META_RIGHT",
+    KeyCodeFunction:                  "FUNCTION",
+    KeyCodeSysrq:                     "SYSRQ",
+    KeyCodeBreak:                     "BREAK",
+    KeyCodeMoveHome:                  "MOVE_HOME",
+    KeyCodeMoveEnd:                   "MOVE_END",
+    KeyCodeInsert:                    "INSERT",
+    KeyCodeForward:                   "FORWARD",
+    KeyCodeMediaPlay:                 "MEDIA_PLAY",
+    KeyCodeMediaPause:                "MEDIA_PAUSE",
+    KeyCodeMediaClose:                "MEDIA_CLOSE",
+    KeyCodeMediaEject:                "MEDIA_EJECT",
+    KeyCodeMediaRecord:               "MEDIA_RECORD",
+    KeyCodeF1:                        "F1",
+    KeyCodeF2:                        "F2",
+    KeyCodeF3:                        "F3",
+    KeyCodeF4:                        "F4",
+    KeyCodeF5:                        "F5",
+    KeyCodeF6:                        "F6",
+    KeyCodeF7:                        "F7",
+    KeyCodeF8:                        "F8",
+    KeyCodeF9:                        "F9",
+    KeyCodeF10:                       "F10",
+    KeyCodeF11:                       "F11",
+    KeyCodeF12:                       "F12",
+    KeyCodeNumLock:                   "NUM_LOCK",
+    KeyCodeNumpad0:                   "NUMPAD_0",
+    KeyCodeNumpad1:                   "NUMPAD_1",
+    KeyCodeNumpad2:                   "NUMPAD_2",
+    KeyCodeNumpad3:                   "NUMPAD_3",
+    KeyCodeNumpad4:                   "NUMPAD_4",
+    KeyCodeNumpad5:                   "NUMPAD_5",
+    KeyCodeNumpad6:                   "NUMPAD_6",
+    KeyCodeNumpad7:                   "NUMPAD_7",
+    KeyCodeNumpad8:                   "NUMPAD_8",
+    KeyCodeNumpad9:                   "NUMPAD_9",
+    KeyCodeNumpadDivide:              "NUMPAD_DIVIDE",
+    KeyCodeNumpadMultiply:            "NUMPAD_MULTIPLY",
+    KeyCodeNumpadSubtract:            "NUMPAD_SUBTRACT",
+    KeyCodeNumpadAdd:                 "NUMPAD_ADD",
+    KeyCodeNumpadDot:                 "NUMPAD_DOT",
+    KeyCodeNumpadComma:               "NUMPAD_COMMA",
+    KeyCodeNumpadEnter:               "NUMPAD_ENTER",
+    KeyCodeNumpadEquals:              "NUMPAD_EQUALS",
+    KeyCodeNumpadLeftParen:           "NUMPAD_LEFT_PAREN",
+    KeyCodeNumpadRightParen:          "NUMPAD_RIGHT_PAREN",
+    KeyCodeVolumeMute:                "VOLUME_MUTE",
+    KeyCodeInfo:                      "INFO",
+    KeyCodeChannelUp:                 "CHANNEL_UP",
+    KeyCodeChannelDown:               "CHANNEL_DOWN",
+    KeyCodeZoomIn:                    "ZOOM_IN",
+    KeyCodeZoomOut:                   "ZOOM_OUT",
+    KeyCodeTv:                        "TV",
+    KeyCodeWindow:                    "WINDOW",
+    KeyCodeGuide:                     "GUIDE",
+    KeyCodeDvr:                       "DVR",
+    KeyCodeBookmark:                  "BOOKMARK",
+    KeyCodeCaptions:                  "CAPTIONS",
+    KeyCodeSettings:                  "SETTINGS",
+    KeyCodeTvPower:                   "TV_POWER",
+    KeyCodeTvInput:                   "TV_INPUT",
+    KeyCodeStbPower:                  "STB_POWER",
+    KeyCodeStbInput:                  "STB_INPUT",
+    KeyCodeAvrPower:                  "AVR_POWER",
+    KeyCodeAvrInput:                  "AVR_INPUT",
+    KeyCodeProgRed:                   "PROG_RED",
+    KeyCodeProgGreen:                 "PROG_GREEN",
+    KeyCodeProgYellow:                "PROG_YELLOW",
+    KeyCodeProgBlue:                  "PROG_BLUE",
+    KeyCodeAppSwitch:                 "APP_SWITCH",
+    KeyCodeButton1:                   "BUTTON_1",
+    KeyCodeButton2:                   "BUTTON_2",
+    KeyCodeButton3:                   "BUTTON_3",
+    KeyCodeButton4:                   "BUTTON_4",
+    KeyCodeButton5:                   "BUTTON_5",
+    KeyCodeButton6:                   "BUTTON_6",
+    KeyCodeButton7:                   "BUTTON_7",
+    KeyCodeButton8:                   "BUTTON_8",
+    KeyCodeButton9:                   "BUTTON_9",
+    KeyCodeButton10:                  "BUTTON_10",
+    KeyCodeButton11:                  "BUTTON_11",
+    KeyCodeButton12:                  "BUTTON_12",
+    KeyCodeButton13:                  "BUTTON_13",
+    KeyCodeButton14:                  "BUTTON_14",
+    KeyCodeButton15:                  "BUTTON_15",
+    KeyCodeButton16:                  "BUTTON_16",
+    KeyCodeLanguageSwitch:            "LANGUAGE_SWITCH",
+    KeyCodeMannerMode:                "MANNER_MODE",
+    KeyCode3DMode:                    "3D_MODE",
+    KeyCodeContacts:                  "CONTACTS",
+    KeyCodeCalendar:                  "CALENDAR",
+    KeyCodeMusic:                     "MUSIC",
+    KeyCodeCalculator:                "CALCULATOR",
+    KeyCodeZenkakuHankaku:            "ZENKAKU_HANKAKU",
+    KeyCodeEisu:                      "EISU",
+    KeyCodeMuhenkan:                  "MUHENKAN",
+    KeyCodeHenkan:                    "HENKAN",
+    KeyCodeKatakanaHiragana:          "KATAKANA_HIRAGANA",
+    KeyCodeYen:                       "YEN",
+    KeyCodeRo:                        "RO",
+    KeyCodeKana:                      "KANA",
+    KeyCodeAssist:                    "ASSIST",
+    KeyCodeBrightnessDown:            "BRIGHTNESS_DOWN",
+    KeyCodeBrightnessUp:              "BRIGHTNESS_UP",
+    KeyCodeMediaAudioTrack:           "MEDIA_AUDIO_TRACK",
+    KeyCodeSleep:                     "SLEEP",
+    KeyCodeWakeup:                    "WAKEUP",
+    KeyCodePairing:                   "PAIRING",
+    KeyCodeMediaTopMenu:              "MEDIA_TOP_MENU",
+    KeyCode11:                        "11",
+    KeyCode12:                        "12",
+    KeyCodeLastChannel:               "LAST_CHANNEL",
+    KeyCodeTvDataService:             "TV_DATA_SERVICE",
+    KeyCodeVoiceAssist:               "VOICE_ASSIST",
+    KeyCodeTvRadioService:            "TV_RADIO_SERVICE",
+    KeyCodeTvTeletext:                "TV_TELETEXT",
+    KeyCodeTvNumberEntry:             "TV_NUMBER_ENTRY",
+    KeyCodeTvTerrestrialAnalog:       "TV_TERRESTRIAL_ANALOG",
+    KeyCodeTvTerrestrialDigital:      "TV_TERRESTRIAL_DIGITAL",
+    KeyCodeTvSatellite:               "TV_SATELLITE",
+    KeyCodeTvSatelliteBs:             "TV_SATELLITE_BS",
+    KeyCodeTvSatelliteCs:             "TV_SATELLITE_CS",
+    KeyCodeTvSatelliteService:        "TV_SATELLITE_SERVICE",
+    KeyCodeTvNetwork:                 "TV_NETWORK",
+    KeyCodeTvAntennaCable:            "TV_ANTENNA_CABLE",
+    KeyCodeTvInputHdmi1:              "TV_INPUT_HDMI_1",
+    KeyCodeTvInputHdmi2:              "TV_INPUT_HDMI_2",
+    KeyCodeTvInputHdmi3:              "TV_INPUT_HDMI_3",
+    KeyCodeTvInputHdmi4:              "TV_INPUT_HDMI_4",
+    KeyCodeTvInputComposite1:         "TV_INPUT_COMPOSITE_1",
+    KeyCodeTvInputComposite2:         "TV_INPUT_COMPOSITE_2",
+    KeyCodeTvInputComponent1:         "TV_INPUT_COMPONENT_1",
+    KeyCodeTvInputComponent2:         "TV_INPUT_COMPONENT_2",
+    KeyCodeTvInputVga1:               "TV_INPUT_VGA_1",
+    KeyCodeTvAudioDescription:        "TV_AUDIO_DESCRIPTION",
+    KeyCodeTvAudioDescriptionMixUp:   "TV_AUDIO_DESCRIPTION_MIX_UP",
+    KeyCodeTvAudioDescriptionMixDown: "TV_AUDIO_DESCRIPTION_MIX_DOWN",
+    KeyCodeTvZoomMode:                "TV_ZOOM_MODE",
+    KeyCodeTvContentsMenu:            "TV_CONTENTS_MENU",
+    KeyCodeTvMediaContextMenu:        "TV_MEDIA_CONTEXT_MENU",
+    KeyCodeTvTimerProgramming:        "TV_TIMER_PROGRAMMING",
+    KeyCodeHelp:                      "HELP",
+    KeyCodeNavigatePrevious:          "NAVIGATE_PREVIOUS",
+    KeyCodeNavigateNext:              "NAVIGATE_NEXT",
+    KeyCodeNavigateIn:                "NAVIGATE_IN",
+    KeyCodeNavigateOut:               "NAVIGATE_OUT",
+    KeyCodeStemPrimary:               "STEM_PRIMARY",
+    KeyCodeStem1:                     "STEM_1",
+    KeyCodeStem2:                     "STEM_2",
+    KeyCodeStem3:                     "STEM_3",
+    KeyCodeDpadUpLeft:                "DPAD_UP_LEFT",
+    KeyCodeDpadDownLeft:              "DPAD_DOWN_LEFT",
+    KeyCodeDpadUpRight:               "DPAD_UP_RIGHT",
+    KeyCodeDpadDownRight:             "DPAD_DOWN_RIGHT",
+    KeyCodeMediaSkipForward:          "MEDIA_SKIP_FORWARD",
+    KeyCodeMediaSkipBackward:         "MEDIA_SKIP_BACKWARD",
+    KeyCodeMediaStepForward:          "MEDIA_STEP_FORWARD",
+    KeyCodeMediaStepBackward:         "MEDIA_STEP_BACKWARD",
+    KeyCodeSoftSleep:                 "SOFT_SLEEP",
+    KeyCodeCut:                       "CUT",
+    KeyCodeCopy:                      "COPY",
+    KeyCodePaste:                     "PASTE",
+    KeyCodeSystemNavigationUp:        "SYSTEM_NAVIGATION_UP",
+    KeyCodeSystemNavigationDown:      "SYSTEM_NAVIGATION_DOWN",
+    KeyCodeSystemNavigationLeft:      "SYSTEM_NAVIGATION_LEFT",
+    KeyCodeSystemNavigationRight:     "SYSTEM_NAVIGATION_RIGHT",
+    KeyCodeAllApps:                   "ALL_APPS",
+    KeyCodeRefresh:                   "REFRESH",
+    KeyCodeThumbsUp:                  "THUMBS_UP",
+    KeyCodeThumbsDown:                "THUMBS_DOWN",
+    KeyCodeProfileSwitch:             "PROFILE_SWITCH",
+    KeyCodeVideoApp1:                 "VIDEO_APP_1",
+    KeyCodeVideoApp2:                 "VIDEO_APP_2",
+    KeyCodeVideoApp3:                 "VIDEO_APP_3",
+    KeyCodeVideoApp4:                 "VIDEO_APP_4",
+    KeyCodeVideoApp5:                 "VIDEO_APP_5",
+    KeyCodeVideoApp6:                 "VIDEO_APP_6",
+    KeyCodeVideoApp7:                 "VIDEO_APP_7",
+    KeyCodeVideoApp8:                 "VIDEO_APP_8",
+    KeyCodeFeaturedApp1:              "FEATURED_APP_1",
+    KeyCodeFeaturedApp2:              "FEATURED_APP_2",
+    KeyCodeFeaturedApp3:              "FEATURED_APP_3",
+    KeyCodeFeaturedApp4:              "FEATURED_APP_4",
+    KeyCodeDemoApp1:                  "DEMO_APP_1",
+    KeyCodeDemoApp2:                  "DEMO_APP_2",
+    KeyCodeDemoApp3:                  "DEMO_APP_3",
+    KeyCodeDemoApp4:                  "DEMO_APP_4",
+    KeyCodeKeyboardBacklightDown:     "KEYBOARD_BACKLIGHT_DOWN",
+    KeyCodeKeyboardBacklightUp:       "KEYBOARD_BACKLIGHT_UP",
+    KeyCodeKeyboardBacklightToggle:   "KEYBOARD_BACKLIGHT_TOGGLE",
+    KeyCodeStylusButtonPrimary:       "STYLUS_BUTTON_PRIMARY",
+    KeyCodeStylusButtonSecondary:     "STYLUS_BUTTON_SECONDARY",
+    KeyCodeStylusButtonTertiary:      "STYLUS_BUTTON_TERTIARY",
+    KeyCodeStylusButtonTail:          "STYLUS_BUTTON_TAIL",
+    KeyCodeRecentApps:                "RECENT_APPS",
+    KeyCodeMacro1:                    "MACRO_1",
+    KeyCodeMacro2:                    "MACRO_2",
+    KeyCodeMacro3:                    "MACRO_3",
+    KeyCodeMacro4:                    "MACRO_4",
+}
+
+// String 返回按键码的常量名，如"KEYCODE_BACK"
+func (k KeyCode) String() string {
+    if name, ok := keyCodeNames[k]; ok {
+        return "KEYCODE_" + name
+    }
+    return "KeyCode(" + strconv.Itoa(int(k)) + ")"
+}
+
+// IsValid 判断按键码是否可以发送给设备
+// 只要求大于0，不要求在常量表中：新系统版本和厂商会增加按键码，名称表只用于String
+func (k KeyCode) IsValid() bool {
+    return k > KeyCodeUnknown
+}
+
+// ParseKeyCode 根据名称或数字解析按键码
+// 支持"KEYCODE_BACK"、"BACK"、"back"和"4"等写法
+// 0、负数和KEYCODE_UNKNOWN不是有效的按键码，返回错误
+func ParseKeyCode(s string) (KeyCode, error) {
+    s = strings.TrimSpace(s)
+    if n, err := strconv.Atoi(s); err == nil {
+        if code := KeyCode(n); code.IsValid() {
+            return code, nil
+        }
+        return KeyCodeUnknown, fmt.Errorf("androidbot: invalid key code %q", s)
+    }
+    name := strings.TrimPrefix(strings.ToUpper(s), "KEYCODE_")
+    for code, n := range keyCodeNames {
+        if n == name && code.IsValid() {
+            return code, nil
+        }
+    }
+    return KeyCodeUnknown, fmt.Errorf("androidbot: unknown key code %q", s)
+}
+
+// MetaState 表示按键事件的修饰键状态，取值与android.view.KeyEvent中的META_*常量一致
+// 多个修饰键可以用|组合，如androidbot.MetaCtrlOn|androidbot.MetaShiftOn
+type MetaState int
+
+const (
+    MetaShiftOn      MetaState = 0x1
+    MetaAltOn        MetaState = 0x2
+    MetaSymOn        MetaState = 0x4
+    MetaFunctionOn   MetaState = 0x8
+    MetaAltLeftOn    MetaState = 0x10
+    MetaAltRightOn   MetaState = 0x20
+    MetaShiftLeftOn  MetaState = 0x40
+    MetaShiftRightOn MetaState = 0x80
+    MetaCtrlOn       MetaState = 0x1000
+    MetaCtrlLeftOn   MetaState = 0x2000
+    MetaCtrlRightOn  MetaState = 0x4000
+    MetaMetaOn       MetaState = 0x10000
+    MetaMetaLeftOn   MetaState = 0x20000
+    MetaMetaRightOn  MetaState = 0x40000
+    MetaCapsLockOn   MetaState = 0x100000
+    MetaNumLockOn    MetaState = 0x200000
+    MetaScrollLockOn MetaState = 0x400000
+)
+
+// defaultLongPressTime 是长按按键的默认时间，超过系统的长按阈值(500毫秒)
+const defaultLongPressTime = time.Second
+
+// 实现AndroidBot接口的LongPressKey方法
+func (b *androidBotImpl) LongPressKey(keyCode KeyCode, duration time.Duration) error {
+    if !keyCode.IsValid() {
+        return fmt.Errorf("androidbot: invalid key code %s", keyCode)
+    }
+    if duration <= 0 {
+        duration = defaultLongPressTime
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的SendKeyCombo方法
+func (b *androidBotImpl) SendKeyCombo(keyCode KeyCode, meta MetaState) error {
+    if !keyCode.IsValid() {
+        return fmt.Errorf("androidbot: invalid key code %s", keyCode)
+    }
+    if meta == 0 {
+        return b.SendKeyEvent(keyCode)
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的Back方法
+func (b *androidBotImpl) Back() error {
+    return b.SendKeyEvent(KeyCodeBack)
+}
+
+// 实现AndroidBot接口的Home方法
+func (b *androidBotImpl) Home() error {
+    return b.SendKeyEvent(KeyCodeHome)
+}
+
+// 实现AndroidBot接口的Recents方法
+func (b *androidBotImpl) Recents() error {
+    return b.SendKeyEvent(KeyCodeAppSwitch)
+}
+
+// 实现AndroidBot接口的Power方法
+func (b *androidBotImpl) Power() error {
+    return b.SendKeyEvent(KeyCodePower)
+}
+
+// 实现AndroidBot接口的VolumeUp方法
+func (b *androidBotImpl) VolumeUp() error {
+    return b.SendKeyEvent(KeyCodeVolumeUp)
+}
+
+// 实现AndroidBot接口的VolumeDown方法
+func (b *androidBotImpl) VolumeDown() error {
+    return b.SendKeyEvent(KeyCodeVolumeDown)
+}
+
+// 实现AndroidBot接口的Enter方法
+func (b *androidBotImpl) Enter() error {
+    return b.SendKeyEvent(KeyCodeEnter)
+}
+
+// 实现AndroidBot接口的Delete方法
+func (b *androidBotImpl) Delete() error {
+    return b.SendKeyEvent(KeyCodeDel)
+}
@@ -0,0 +1,172 @@
+package androidbot
+
+import (
+    "context"
+    "errors"
+    "regexp"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+// verificationCodePattern 匹配4到8位的数字验证码
+var verificationCodePattern = regexp.MustCompile(`(?:^|\D)(\d{4,8})(?:\D|$)`)
+
+// digitRunPattern 匹配连续的数字
+var digitRunPattern = regexp.MustCompile(`\d+`)
+
+// codeKeywordPattern 匹配验证码附近常见的提示词
+var codeKeywordPattern = regexp.MustCompile(`(?i)验证码|校验码|动态码|动态密码|code|otp`)
+
+// maxCodeKeywordGap 是提示词与验证码之间最多相隔的字符数
+const maxCodeKeywordGap = 6
+
+// codeGapBreakers 是提示词与数字之间出现时说明二者不属于同一句的标点
+const codeGapBreakers = "，,。;；!！?？\n"
+
+// Notification 表示通知栏中的一条通知
+// Key: 系统分配的唯一标识，用于点击和移除通知
+// Time: 通知发出的时间
+// Ongoing: 是否为常驻通知，常驻通知不能被移除
+type Notification struct {
+    Key         string
+    PackageName string
+    Title       string
+    Text        string
+    Time        time.Time
+    Ongoing     bool
+}
+
+// Code 返回通知标题或内容中的短信验证码
+// 优先返回紧挨着"验证码"、"code"、"OTP"等提示词的4到8位数字，
+// 如"尾号1234的卡…验证码567890"返回567890；没有提示词时返回第一个4到8位的数字
+// 没有找到时返回空字符串
+func (n Notification) Code() string {
+    for _, s := range []string{n.Text, n.Title} {
+        if code := keywordCode(s); code != "" {
+            return code
+        }
+    }
+    for _, s := range []string{n.Text, n.Title} {
+        if m := verificationCodePattern.FindStringSubmatch(s); m != nil {
+            return m[1]
+        }
+    }
+    return ""
+}
+
+// keywordCode 返回s中离提示词最近的4到8位数字
+// 距离相同时优先取提示词之后的数字
+func keywordCode(s string) string {
+    keywords := codeKeywordPattern.FindAllStringIndex(s, -1)
+    if keywords == nil {
+        return ""
+    }
+    code, best := "", maxCodeKeywordGap+1
+    for _, m := range digitRunPattern.FindAllStringIndex(s, -1) {
+        start, end := m[0], m[1]
+        if n := end - start; n < 4 || n > 8 {
+            continue
+        }
+        for _, kw := range keywords {
+            var between string
+            var gap int
+            switch {
+            case kw[1] <= start:
+                between = s[kw[1]:start]
+                gap = utf8.RuneCountInString(between)
+            case end <= kw[0]:
+                // 数字在提示词之前时多算一个字符，使相同距离时提示词之后的数字优先
+                between = s[end:kw[0]]
+                gap = utf8.RuneCountInString(between) + 1
+            default:
+                continue
+            }
+            if gap < best && !strings.ContainsAny(between, codeGapBreakers) {
+                code, best = s[start:end], gap
+            }
+        }
+    }
+    return code
+}
+
+// 实现AndroidBot接口的ListNotifications方法
+func (b *androidBotImpl) ListNotifications() ([]Notification, error) {
+    // 实际实现将在后续添加
+    // 这里返回空切片和nil作为占位符
+    return []Notification{}, nil
+}
+
+// 实现AndroidBot接口的WaitForNotification方法
+func (b *androidBotImpl) WaitForNotification(ctx context.Context, match func(Notification) bool, timeout time.Duration) (Notification, error) {
+    if match == nil {
+        return Notification{}, errors.New("androidbot: notification match function is required")
+    }
+    // 记录调用时已经存在的通知，只返回之后新发出或更新的通知
+    since := time.Now()
+    existing, err := b.ListNotifications()
+    if err != nil {
+        return Notification{}, err
+    }
+    seen := make(map[string]time.Time, len(existing))
+    for _, n := range existing {
+        seen[n.Key] = n.Time
+    }
+
+    var found Notification
+    err = b.poll(ctx, timeout, "notification", func() (bool, error) {
+        notifications, err := b.ListNotifications()
+        if err != nil {
+            return false, err
+        }
+        for _, n := range notifications {
+            postedAt, old := seen[n.Key]
+            if old && !n.Time.After(postedAt) && !n.Time.After(since) {
+                continue
+            }
+            if match(n) {
+                found = n
+                return true, nil
+            }
+        }
+        return false, nil
+    })
+    return found, err
+}
+
+// 实现AndroidBot接口的TapNotification方法
+func (b *androidBotImpl) TapNotification(n Notification) error {
+    if n.Key == "" {
+        return errors.New("androidbot: notification key is required")
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的DismissNotification方法
+func (b *androidBotImpl) DismissNotification(n Notification) error {
+    if n.Key == "" {
+        return errors.New("androidbot: notification key is required")
+    }
+    if n.Ongoing {
+        return errors.New("androidbot: ongoing notifications cannot be dismissed")
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的OpenNotifications方法
+func (b *androidBotImpl) OpenNotifications() error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的CloseNotifications方法
+func (b *androidBotImpl) CloseNotifications() error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
@@ -0,0 +1,26 @@
+package androidbot
+
+import "testing"
+
+func TestNotificationCode(t *testing.T) {
+    tests := []struct {
+        text string
+        want string
+    }{
+        // 优先取提示词旁边的数字
+        {"尾号1234的卡…验证码567890", "567890"},
+        {"567890是您的验证码，尾号1234的卡请勿泄露", "567890"},
+        {"验证码：123456，5分钟内有效", "123456"},
+        {"Your code is 8812. Ref 1234", "8812"},
+        {"Ref 1234 OTP 5678", "5678"},
+        // 没有提示词时取第一个4到8位的数字
+        {"订单20240101已发货", "20240101"},
+        {"编号123456789", ""},
+        {"没有数字", ""},
+    }
+    for _, tt := range tests {
+        if got := (Notification{Text: tt.text}).Code(); got != tt.want {
+            t.Errorf("Code(%q) = %q, want %q", tt.text, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,170 @@
+package androidbot
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "regexp"
+    "strings"
+    "time"
+)
+
+// ErrPackageNotFound 表示设备上没有安装指定的应用
+var ErrPackageNotFound = errors.New("androidbot: package not found")
+
+// packageNamePattern 是合法的Android包名格式：由点分隔的Java标识符
+// 系统应用的包名可以不含点，如"android"
+var packageNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// apkMagic 是APK(zip)文件的文件头
+var apkMagic = []byte("PK\x03\x04")
+
+// PackageFilter 表示GetInstalledPackages的过滤条件
+type PackageFilter int
+
+const (
+    PackagesAll        PackageFilter = iota // 所有应用
+    PackagesSystem                          // 只包含系统应用
+    PackagesThirdParty                      // 只包含用户安装的第三方应用
+)
+
+// PackageInfo 表示已安装应用的信息
+type PackageInfo struct {
+    PackageName      string
+    VersionName      string
+    VersionCode      int64
+    FirstInstallTime time.Time
+    LastUpdateTime   time.Time
+    System           bool
+    Enabled          bool
+}
+
+// InstallOptions 表示安装APK的选项
+// Replace: 已安装时覆盖安装并保留数据
+// AllowDowngrade: 允许安装比已安装版本更低的版本
+// GrantPermissions: 安装时授予清单中声明的所有运行时权限
+type InstallOptions struct {
+    Replace          bool
+    AllowDowngrade   bool
+    GrantPermissions bool
+}
+
+// validatePackageName 检查包名格式
+func validatePackageName(packageName string) error {
+    if !packageNamePattern.MatchString(packageName) {
+        return fmt.Errorf("androidbot: invalid package name %q", packageName)
+    }
+    return nil
+}
+
+// normalizePermission 补全权限名，"CAMERA"等价于"android.permission.CAMERA"
+func normalizePermission(permission string) (string, error) {
+    if permission == "" {
+        return "", errors.New("androidbot: permission is required")
+    }
+    if !strings.Contains(permission, ".") {
+        permission = "android.permission." + permission
+    }
+    return permission, nil
+}
+
+// 实现AndroidBot接口的GetPackageInfo方法
+func (b *androidBotImpl) GetPackageInfo(packageName string) (PackageInfo, error) {
+    if err := validatePackageName(packageName); err != nil {
+        return PackageInfo{}, err
+    }
+    // 实际实现将在后续添加
+    // 这里返回ErrPackageNotFound作为占位符
+    return PackageInfo{}, fmt.Errorf("%w: %s", ErrPackageNotFound, packageName)
+}
+
+// 实现AndroidBot接口的InstallAPK方法
+func (b *androidBotImpl) InstallAPK(apkPath string, options InstallOptions) error {
+    f, err := os.Open(apkPath)
+    if err != nil {
+        return fmt.Errorf("failed to open apk: %w", err)
+    }
+    defer f.Close()
+    info, err := f.Stat()
+    if err != nil {
+        return fmt.Errorf("failed to open apk: %w", err)
+    }
+    if info.IsDir() {
+        return fmt.Errorf("androidbot: %s is a directory", apkPath)
+    }
+    header := make([]byte, len(apkMagic))
+    if _, err := io.ReadFull(f, header); err != nil || !bytes.Equal(header, apkMagic) {
+        return fmt.Errorf("androidbot: %s is not an apk file", apkPath)
+    }
+    if _, err := f.Seek(0, io.SeekStart); err != nil {
+        return fmt.Errorf("failed to read apk: %w", err)
+    }
+    // APK可能有上百MB，按块发送，不整体读入内存
+    return b.installStream(filepath.Base(apkPath), info.Size(), f, options)
+}
+
+// installStream 将APK内容流式发送到设备并安装
+func (b *androidBotImpl) installStream(name string, size int64, r io.Reader, options InstallOptions) error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的UninstallApp方法
+func (b *androidBotImpl) UninstallApp(packageName string, keepData bool) error {
+    if err := validatePackageName(packageName); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的ClearAppData方法
+func (b *androidBotImpl) ClearAppData(packageName string) error {
+    if err := validatePackageName(packageName); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的ClearAppCache方法
+func (b *androidBotImpl) ClearAppCache(packageName string) error {
+    if err := validatePackageName(packageName); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的GrantPermission方法
+func (b *androidBotImpl) GrantPermission(packageName, permission string) error {
+    if err := validatePackageName(packageName); err != nil {
+        return err
+    }
+    if _, err := normalizePermission(permission); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现AndroidBot接口的RevokePermission方法
+func (b *androidBotImpl) RevokePermission(packageName, permission string) error {
+    if err := validatePackageName(packageName); err != nil {
+        return err
+    }
+    if _, err := normalizePermission(permission); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
@@ -0,0 +1,195 @@
+package androidbot
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "time"
+)
+
+// 等待操作的默认参数
+const (
+    defaultWaitTimeout  = 10 * time.Second
+    defaultPollInterval = 500 * time.Millisecond
+)
+
+// WaitTimeoutError 表示等待的条件在超时时间内没有满足
+// Condition: 等待条件的描述
+// Err: 最后一次检查时遇到的错误，可以为nil
+type WaitTimeoutError struct {
+    Condition string
+    Timeout   time.Duration
+    Err       error
+}
+
+func (e *WaitTimeoutError) Error() string {
+    msg := fmt.Sprintf("androidbot: timed out after %s waiting for %s", e.Timeout, e.Condition)
+    if e.Err != nil {
+        msg += ": " + e.Err.Error()
+    }
+    return msg
+}
+
+func (e *WaitTimeoutError) Unwrap() error {
+    return e.Err
+}
+
+// WithWaitTimeout 设置等待方法的默认超时时间
+// timeout: 调用等待方法时传入0则使用该值，默认10秒
+func WithWaitTimeout(timeout time.Duration) AndroidBotOption {
+    return func(b *androidBotImpl) {
+        b.waitTimeout = timeout
+    }
+}
+
+// WithPollInterval 设置等待方法检查条件的间隔
+// interval: 两次检查之间的间隔，默认500毫秒
+func WithPollInterval(interval time.Duration) AndroidBotOption {
+    return func(b *androidBotImpl) {
+        b.pollInterval = interval
+    }
+}
+
+// poll 每隔pollInterval检查一次condition，直到满足、出错、超时或ctx取消
+// condition返回的错误中，*ElementNotFoundError视为条件暂未满足，其他错误立即返回
+func (b *androidBotImpl) poll(ctx context.Context, timeout time.Duration, what string, condition func() (bool, error)) error {
+    if ctx == nil {
+        ctx = context.Background()
+    }
+    if timeout <= 0 {
+        timeout = b.waitTimeout
+    }
+    deadline := time.NewTimer(timeout)
+    defer deadline.Stop()
+    interval := b.pollInterval
+    if interval <= 0 {
+        interval = defaultPollInterval
+    }
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    var lastErr error
+    for {
+        ok, err := condition()
+        if err != nil && !errors.Is(err, ErrNodeNotFound) {
+            return err
+        }
+        if ok {
+            return nil
+        }
+        lastErr = err
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-deadline.C:
+            return &WaitTimeoutError{Condition: what, Timeout: timeout, Err: lastErr}
+        case <-ticker.C:
+        }
+    }
+}
+
+// 实现AndroidBot接口的WaitForElement方法
+func (b *androidBotImpl) WaitForElement(ctx context.Context, selector Selector, timeout time.Duration) (*AndroidElement, error) {
+    if err := selector.validate(); err != nil {
+        return nil, err
+    }
+    element := &AndroidElement{bot: b, selector: selector}
+    err := b.poll(ctx, timeout, "element "+selector.String(), func() (bool, error) {
+        // 元素不存在时Refresh返回ErrNodeNotFound，poll会继续等待，其他错误直接返回
+        if err := element.Refresh(); err != nil {
+            return false, err
+        }
+        return true, nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return element, nil
+}
+
+// 实现AndroidBot接口的WaitForElementGone方法
+func (b *androidBotImpl) WaitForElementGone(ctx context.Context, selector Selector, timeout time.Duration) error {
+    if err := selector.validate(); err != nil {
+        return err
+    }
+    return b.poll(ctx, timeout, "element "+selector.String()+" to disappear", func() (bool, error) {
+        hierarchy, err := b.DumpHierarchy()
+        if err != nil {
+            return false, err
+        }
+        nodes, err := selector.nodes(hierarchy)
+        return len(nodes) == 0, err
+    })
+}
+
+// 实现AndroidBot接口的WaitForText方法
+func (b *androidBotImpl) WaitForText(ctx context.Context, selector Selector, match func(text string) bool, timeout time.Duration) (*AndroidElement, error) {
+    if err := selector.validate(); err != nil {
+        return nil, err
+    }
+    if match == nil {
+        return nil, errors.New("androidbot: text match function is required")
+    }
+    element := &AndroidElement{bot: b, selector: selector}
+    err := b.poll(ctx, timeout, "text of element "+selector.String(), func() (bool, error) {
+        if err := element.Refresh(); err != nil {
+            return false, err
+        }
+        return match(element.Text()), nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return element, nil
+}
+
+// 实现AndroidBot接口的WaitForActivity方法
+func (b *androidBotImpl) WaitForActivity(ctx context.Context, packageName, activity string, timeout time.Duration) error {
+    if packageName == "" {
+        return errors.New("androidbot: package name is required")
+    }
+    what := "package " + packageName
+    if activity != "" {
+        what = "activity " + packageName + "/" + activity
+    }
+    return b.poll(ctx, timeout, what, func() (bool, error) {
+        app, err := b.CurrentApp()
+        if err != nil {
+            return false, err
+        }
+        return app.Is(packageName, activity), nil
+    })
+}
+
+// activityMatches 判断当前Activity是否为期望的Activity
+// 期望值可以是完整类名，也可以是以"."开头的相对包名的简写，如".MainActivity"
+func activityMatches(packageName, current, want string) bool {
+    if current == want {
+        return true
+    }
+    if want[0] == '.' {
+        return current == packageName+want
+    }
+    if current != "" && current[0] == '.' {
+        return packageName+current == want
+    }
+    return false
+}
+
+// 实现AndroidBot接口的WaitForStable方法
+func (b *androidBotImpl) WaitForStable(ctx context.Context, timeout time.Duration) (*Hierarchy, error) {
+    var previous, current *Hierarchy
+    err := b.poll(ctx, timeout, "screen to become stable", func() (bool, error) {
+        hierarchy, err := b.DumpHierarchy()
+        if err != nil {
+            return false, err
+        }
+        previous, current = current, hierarchy
+        return previous != nil && bytes.Equal(previous.XML, current.XML), nil
+    })
+    if err != nil {
+        return nil, err
+    }
+    return current, nil
+}
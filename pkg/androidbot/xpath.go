@@ -0,0 +1,855 @@
+package androidbot
+
+import (
+    "fmt"
+    "math"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// 本文件实现在本地层级树上执行的XPath子集，支持：
+//   - 绝对路径和相对路径，"/"、"//"、"."、".."、"*"
+//   - 带括号的路径表达式，谓词作用于整个结果集，如(//android.widget.Button)[1]
+//   - 轴child、descendant、descendant-or-self、self、parent、ancestor、
+//     ancestor-or-self、following-sibling、preceding-sibling，如//Button/parent::*
+//   - 元素名测试，元素名可以是XML标签(node)或class属性，如//android.widget.Button
+//   - 谓词中的属性、字符串、数字(可以为负数)、位置，运算符=、!=、<、<=、>、>=、and、or，
+//     算术运算+、-、*、div、mod，如[last()-1]
+//   - 函数contains、starts-with、ends-with、matches、not、text、position、last、
+//     string-length、normalize-space
+// text()返回节点的text属性，与Appium等工具的习惯一致
+
+// xpathStepKind 表示路径步骤的类型
+type xpathStepKind int
+
+const (
+    stepName xpathStepKind = iota
+    stepSelf
+    stepParent
+)
+
+// xpathStep 是路径中的一个步骤
+// axis为空表示默认的child轴
+type xpathStep struct {
+    kind       xpathStepKind
+    descendant bool
+    axis       string
+    name       string
+    predicates []xpathNode
+}
+
+// xpathAxes 是支持的轴，following、preceding、attribute、namespace轴不支持
+var xpathAxes = map[string]bool{
+    "child":              true,
+    "descendant":         true,
+    "descendant-or-self": true,
+    "self":               true,
+    "parent":             true,
+    "ancestor":           true,
+    "ancestor-or-self":   true,
+    "following-sibling":  true,
+    "preceding-sibling":  true,
+}
+
+// axisNodes 返回node在指定轴上的节点，按轴的方向排列
+// 反向轴从离node最近的节点开始，位置谓词按此顺序计数，如ancestor::*[1]是父节点
+func axisNodes(axis string, node *Node) []*Node {
+    var nodes []*Node
+    switch axis {
+    case "child":
+        return node.Children
+    case "descendant", "descendant-or-self":
+        node.Walk(func(n *Node) bool {
+            if n != node || axis == "descendant-or-self" {
+                nodes = append(nodes, n)
+            }
+            return true
+        })
+    case "self":
+        nodes = []*Node{node}
+    case "parent":
+        if node.Parent != nil {
+            nodes = []*Node{node.Parent}
+        }
+    case "ancestor", "ancestor-or-self":
+        if axis == "ancestor-or-self" {
+            nodes = append(nodes, node)
+        }
+        for p := node.Parent; p != nil; p = p.Parent {
+            nodes = append(nodes, p)
+        }
+    case "following-sibling", "preceding-sibling":
+        if node.Parent == nil {
+            return nil
+        }
+        siblings := node.Parent.Children
+        for i, sibling := range siblings {
+            if sibling != node {
+                continue
+            }
+            if axis == "following-sibling" {
+                return siblings[i+1:]
+            }
+            for j := i - 1; j >= 0; j-- {
+                nodes = append(nodes, siblings[j])
+            }
+        }
+    }
+    return nodes
+}
+
+// xpathExpr 是编译后的XPath表达式
+// group不为nil时表达式以带括号的子表达式开始，如(//Button)[1]/..，
+// groupPredicates作用于子表达式按文档顺序排列的全部结果，steps相对于这些结果执行
+type xpathExpr struct {
+    source          string
+    absolute        bool
+    group           *xpathExpr
+    groupPredicates []xpathNode
+    steps           []xpathStep
+}
+
+// xpathContext 是谓词求值时的上下文
+type xpathContext struct {
+    node *Node
+    pos  int
+    size int
+}
+
+// xpathNode 是谓词表达式的语法树节点
+// 求值结果为string、float64、bool或attrValue之一
+type xpathNode interface {
+    eval(ctx xpathContext) interface{}
+}
+
+// attrValue 是属性引用的求值结果
+// ok为false表示节点没有该属性，此时任何比较都不成立，与XPath的空节点集一致
+type attrValue struct {
+    value string
+    ok    bool
+}
+
+// compileXPath 编译XPath表达式
+func compileXPath(source string) (*xpathExpr, error) {
+    tokens, err := tokenizeXPath(source)
+    if err != nil {
+        return nil, err
+    }
+    p := &xpathParser{source: source, tokens: tokens}
+    expr, err := p.parsePath()
+    if err != nil {
+        return nil, err
+    }
+    return expr, nil
+}
+
+// selectNodes 以ctx为上下文执行表达式，返回按文档顺序排列的匹配节点
+func (e *xpathExpr) selectNodes(ctx *Node) []*Node {
+    top := ctx
+    for top.Parent != nil {
+        top = top.Parent
+    }
+    current := []*Node{ctx}
+    var document *Node
+    if e.group != nil {
+        current = filterPredicates(e.group.selectNodes(ctx), e.groupPredicates)
+    } else if e.absolute {
+        // 虚拟的文档节点，使"/hierarchy"能够匹配根元素
+        document = &Node{Children: []*Node{top}}
+        current = []*Node{document}
+    }
+
+    for _, step := range e.steps {
+        if len(current) == 0 {
+            break
+        }
+        seen := map[*Node]bool{}
+        var next []*Node
+        for _, node := range current {
+            for _, group := range step.groups(node) {
+                for _, match := range step.filter(group) {
+                    if match != document && !seen[match] {
+                        seen[match] = true
+                        next = append(next, match)
+                    }
+                }
+            }
+        }
+        if len(next) > 1 && (step.descendant || step.axis != "" || len(current) > 1) {
+            sortDocumentOrder(top, next)
+        }
+        current = next
+    }
+    return current
+}
+
+// groups 返回步骤在node上下文中的候选节点，按父节点分组
+// 位置谓词在每组内部计算，例如//node[1]匹配每个父节点下的第一个子节点
+func (s xpathStep) groups(node *Node) [][]*Node {
+    var contexts []*Node
+    if s.descendant {
+        node.Walk(func(n *Node) bool {
+            contexts = append(contexts, n)
+            return true
+        })
+    } else {
+        contexts = []*Node{node}
+    }
+    groups := make([][]*Node, 0, len(contexts))
+    for _, c := range contexts {
+        switch s.kind {
+        case stepSelf:
+            groups = append(groups, []*Node{c})
+        case stepParent:
+            if c.Parent != nil {
+                groups = append(groups, []*Node{c.Parent})
+            }
+        default:
+            if s.axis != "" {
+                groups = append(groups, axisNodes(s.axis, c))
+            } else {
+                groups = append(groups, c.Children)
+            }
+        }
+    }
+    return groups
+}
+
+// filter 按元素名和谓词过滤一组候选节点
+func (s xpathStep) filter(candidates []*Node) []*Node {
+    var matched []*Node
+    for _, n := range candidates {
+        if s.kind != stepName || s.name == "*" || s.name == n.Tag || s.name == n.Class {
+            matched = append(matched, n)
+        }
+    }
+    return filterPredicates(matched, s.predicates)
+}
+
+// filterPredicates 依次用谓词过滤节点，位置按nodes中的顺序从1开始计算
+// 数字结果表示位置谓词，如[2]等价于[position()=2]
+func filterPredicates(nodes []*Node, predicates []xpathNode) []*Node {
+    for _, pred := range predicates {
+        var kept []*Node
+        for i, n := range nodes {
+            v := pred.eval(xpathContext{node: n, pos: i + 1, size: len(nodes)})
+            if num, ok := v.(float64); ok {
+                if int(num) == i+1 && num == math.Trunc(num) {
+                    kept = append(kept, n)
+                }
+            } else if xpathBool(v) {
+                kept = append(kept, n)
+            }
+        }
+        nodes = kept
+    }
+    return nodes
+}
+
+// sortDocumentOrder 将节点按文档顺序排序
+func sortDocumentOrder(top *Node, nodes []*Node) {
+    order := map[*Node]int{}
+    top.Walk(func(n *Node) bool {
+        order[n] = len(order)
+        return true
+    })
+    sort.SliceStable(nodes, func(i, j int) bool { return order[nodes[i]] < order[nodes[j]] })
+}
+
+// xpathString 将求值结果转换为字符串
+func xpathString(v interface{}) string {
+    switch v := v.(type) {
+    case string:
+        return v
+    case attrValue:
+        return v.value
+    case float64:
+        return strconv.FormatFloat(v, 'f', -1, 64)
+    case bool:
+        return strconv.FormatBool(v)
+    }
+    return ""
+}
+
+// xpathNumber 将求值结果转换为数字，无法转换时返回NaN
+func xpathNumber(v interface{}) float64 {
+    switch v := v.(type) {
+    case float64:
+        return v
+    case bool:
+        if v {
+            return 1
+        }
+        return 0
+    }
+    f, err := strconv.ParseFloat(strings.TrimSpace(xpathString(v)), 64)
+    if err != nil {
+        return math.NaN()
+    }
+    return f
+}
+
+// xpathBool 将求值结果转换为布尔值
+func xpathBool(v interface{}) bool {
+    switch v := v.(type) {
+    case bool:
+        return v
+    case attrValue:
+        return v.ok
+    case float64:
+        return v != 0 && !math.IsNaN(v)
+    case string:
+        return v != ""
+    }
+    return false
+}
+
+// literalNode 是字符串或数字常量
+type literalNode struct {
+    value interface{}
+}
+
+func (n literalNode) eval(xpathContext) interface{} {
+    return n.value
+}
+
+// attrNode 是属性引用，如@text
+type attrNode struct {
+    name string
+}
+
+func (n attrNode) eval(ctx xpathContext) interface{} {
+    value, ok := ctx.node.Attrs[n.name]
+    return attrValue{value: value, ok: ok}
+}
+
+// negateNode 是一元负号，如-1
+type negateNode struct {
+    operand xpathNode
+}
+
+func (n negateNode) eval(ctx xpathContext) interface{} {
+    return -xpathNumber(n.operand.eval(ctx))
+}
+
+// arithNode 是算术运算，操作数按XPath规则转换为数字
+type arithNode struct {
+    op          string
+    left, right xpathNode
+}
+
+func (n arithNode) eval(ctx xpathContext) interface{} {
+    l, r := xpathNumber(n.left.eval(ctx)), xpathNumber(n.right.eval(ctx))
+    switch n.op {
+    case "+":
+        return l + r
+    case "-":
+        return l - r
+    case "*":
+        return l * r
+    case "div":
+        return l / r
+    case "mod":
+        return math.Mod(l, r)
+    }
+    return math.NaN()
+}
+
+// binaryNode 是二元运算
+type binaryNode struct {
+    op          string
+    left, right xpathNode
+}
+
+func (n binaryNode) eval(ctx xpathContext) interface{} {
+    switch n.op {
+    case "or":
+        return xpathBool(n.left.eval(ctx)) || xpathBool(n.right.eval(ctx))
+    case "and":
+        return xpathBool(n.left.eval(ctx)) && xpathBool(n.right.eval(ctx))
+    }
+    return xpathCompare(n.op, n.left.eval(ctx), n.right.eval(ctx))
+}
+
+// xpathCompare 按XPath 1.0的规则比较两个值
+func xpathCompare(op string, left, right interface{}) bool {
+    for _, v := range []interface{}{left, right} {
+        if a, ok := v.(attrValue); ok && !a.ok {
+            return false
+        }
+    }
+    _, lb := left.(bool)
+    _, rb := right.(bool)
+    _, ln := left.(float64)
+    _, rn := right.(float64)
+    switch {
+    case op == "=" || op == "!=":
+        var equal bool
+        switch {
+        case lb || rb:
+            equal = xpathBool(left) == xpathBool(right)
+        case ln || rn:
+            equal = xpathNumber(left) == xpathNumber(right)
+        default:
+            equal = xpathString(left) == xpathString(right)
+        }
+        return equal == (op == "=")
+    default:
+        l, r := xpathNumber(left), xpathNumber(right)
+        switch op {
+        case "<":
+            return l < r
+        case "<=":
+            return l <= r
+        case ">":
+            return l > r
+        case ">=":
+            return l >= r
+        }
+    }
+    return false
+}
+
+// funcNode 是函数调用
+type funcNode struct {
+    name string
+    args []xpathNode
+    re   *regexp.Regexp
+}
+
+func (n funcNode) eval(ctx xpathContext) interface{} {
+    arg := func(i int) string { return xpathString(n.args[i].eval(ctx)) }
+    switch n.name {
+    case "text":
+        return attrValue{value: ctx.node.Text, ok: true}
+    case "position":
+        return float64(ctx.pos)
+    case "last":
+        return float64(ctx.size)
+    case "not":
+        return !xpathBool(n.args[0].eval(ctx))
+    case "contains":
+        return strings.Contains(arg(0), arg(1))
+    case "starts-with":
+        return strings.HasPrefix(arg(0), arg(1))
+    case "ends-with":
+        return strings.HasSuffix(arg(0), arg(1))
+    case "matches":
+        return n.re.MatchString(arg(0))
+    case "string-length":
+        return float64(len([]rune(arg(0))))
+    case "normalize-space":
+        return strings.Join(strings.Fields(arg(0)), " ")
+    }
+    return false
+}
+
+// xpathFuncArity 是支持的函数及其参数个数
+var xpathFuncArity = map[string]int{
+    "text":            0,
+    "position":        0,
+    "last":            0,
+    "not":             1,
+    "contains":        2,
+    "starts-with":     2,
+    "ends-with":       2,
+    "matches":         2,
+    "string-length":   1,
+    "normalize-space": 1,
+}
+
+// xpathToken 是词法分析得到的记号
+type xpathToken struct {
+    kind  byte // 'n'名称，'s'字符串，'d'数字，'o'运算符或标点
+    value string
+}
+
+// tokenizeXPath 对XPath表达式进行词法分析
+func tokenizeXPath(s string) ([]xpathToken, error) {
+    var tokens []xpathToken
+    isNameStart := func(c byte) bool { return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 }
+    isNameChar := func(c byte) bool { return isNameStart(c) || c >= '0' && c <= '9' || c == '-' || c == '.' || c == ':' }
+    for i := 0; i < len(s); {
+        c := s[i]
+        switch {
+        case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+            i++
+        case c == '\'' || c == '"':
+            end := strings.IndexByte(s[i+1:], c)
+            if end < 0 {
+                return nil, fmt.Errorf("invalid xpath %q: unterminated string", s)
+            }
+            tokens = append(tokens, xpathToken{'s', s[i+1 : i+1+end]})
+            i += end + 2
+        case c >= '0' && c <= '9':
+            j := i
+            for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+                j++
+            }
+            tokens = append(tokens, xpathToken{'d', s[i:j]})
+            i = j
+        case isNameStart(c):
+            j := i
+            // "::"是轴分隔符，不属于名称
+            for j < len(s) && isNameChar(s[j]) && !strings.HasPrefix(s[j:], "::") {
+                j++
+            }
+            tokens = append(tokens, xpathToken{'n', s[i:j]})
+            i = j
+        default:
+            op := string(c)
+            if i+1 < len(s) {
+                switch two := s[i : i+2]; two {
+                case "//", "..", "::", "!=", "<=", ">=":
+                    op = two
+                }
+            }
+            if len(op) == 1 && !strings.Contains("/[]()@,=<>.*-+", op) {
+                return nil, fmt.Errorf("invalid xpath %q: unexpected %q", s, op)
+            }
+            tokens = append(tokens, xpathToken{'o', op})
+            i += len(op)
+        }
+    }
+    return tokens, nil
+}
+
+// xpathParser 是XPath表达式的递归下降解析器
+type xpathParser struct {
+    source string
+    tokens []xpathToken
+    pos    int
+}
+
+func (p *xpathParser) peek() xpathToken {
+    if p.pos < len(p.tokens) {
+        return p.tokens[p.pos]
+    }
+    return xpathToken{}
+}
+
+func (p *xpathParser) next() xpathToken {
+    t := p.peek()
+    p.pos++
+    return t
+}
+
+// accept 如果下一个记号是指定的运算符则消费它
+func (p *xpathParser) accept(op string) bool {
+    if t := p.peek(); t.kind == 'o' && t.value == op {
+        p.pos++
+        return true
+    }
+    return false
+}
+
+func (p *xpathParser) errorf(format string, args ...interface{}) error {
+    return fmt.Errorf("invalid xpath %q: %s", p.source, fmt.Sprintf(format, args...))
+}
+
+// parsePath 解析完整的表达式，之后不能再有其他记号
+func (p *xpathParser) parsePath() (*xpathExpr, error) {
+    expr, err := p.parseExpr()
+    if err != nil {
+        return nil, err
+    }
+    if p.pos != len(p.tokens) {
+        return nil, p.errorf("unexpected %q", p.peek().value)
+    }
+    return expr, nil
+}
+
+// parseExpr 解析位置路径或带括号的路径表达式，如(//Button)[1]/..
+func (p *xpathParser) parseExpr() (*xpathExpr, error) {
+    expr := &xpathExpr{source: p.source}
+    descendant := false
+    if p.accept("(") {
+        group, err := p.parseExpr()
+        if err != nil {
+            return nil, err
+        }
+        if !p.accept(")") {
+            return nil, p.errorf("expected )")
+        }
+        expr.group = group
+        if expr.groupPredicates, err = p.parsePredicates(); err != nil {
+            return nil, err
+        }
+        switch {
+        case p.accept("//"):
+            descendant = true
+        case p.accept("/"):
+        default:
+            return expr, nil
+        }
+    } else {
+        switch {
+        case p.accept("//"):
+            expr.absolute, descendant = true, true
+        case p.accept("/"):
+            expr.absolute = true
+        }
+    }
+    for {
+        step, err := p.parseStep()
+        if err != nil {
+            return nil, err
+        }
+        step.descendant = descendant
+        expr.steps = append(expr.steps, step)
+        switch {
+        case p.accept("//"):
+            descendant = true
+        case p.accept("/"):
+            descendant = false
+        default:
+            return expr, nil
+        }
+    }
+}
+
+// parsePredicates 解析零个或多个谓词
+func (p *xpathParser) parsePredicates() ([]xpathNode, error) {
+    var predicates []xpathNode
+    for p.accept("[") {
+        pred, err := p.parseOr()
+        if err != nil {
+            return nil, err
+        }
+        if !p.accept("]") {
+            return nil, p.errorf("expected ]")
+        }
+        predicates = append(predicates, pred)
+    }
+    return predicates, nil
+}
+
+// parseStep 解析一个路径步骤
+func (p *xpathParser) parseStep() (xpathStep, error) {
+    var step xpathStep
+    t := p.next()
+    switch {
+    case t.kind == 'o' && t.value == ".":
+        step.kind = stepSelf
+        return step, nil
+    case t.kind == 'o' && t.value == "..":
+        step.kind = stepParent
+        return step, nil
+    case t.kind == 'n' && p.accept("::"):
+        if !xpathAxes[t.value] {
+            return step, p.errorf("unsupported axis %s::", t.value)
+        }
+        step.axis = t.value
+        if err := p.parseNameTest(&step, p.next()); err != nil {
+            return step, err
+        }
+    default:
+        if err := p.parseNameTest(&step, t); err != nil {
+            return step, err
+        }
+    }
+    predicates, err := p.parsePredicates()
+    if err != nil {
+        return step, err
+    }
+    step.predicates = predicates
+    return step, nil
+}
+
+// parseNameTest 解析步骤的元素名测试：元素名、*或node()
+func (p *xpathParser) parseNameTest(step *xpathStep, t xpathToken) error {
+    switch {
+    case t.kind == 'o' && t.value == "*":
+        step.name = "*"
+    case t.kind == 'n':
+        step.name = t.value
+        // node()等价于*
+        if step.name == "node" && p.accept("(") {
+            if !p.accept(")") {
+                return p.errorf("expected )")
+            }
+            step.name = "*"
+        }
+    default:
+        return p.errorf("expected element name, got %q", t.value)
+    }
+    return nil
+}
+
+// parseOr 解析or表达式
+func (p *xpathParser) parseOr() (xpathNode, error) {
+    left, err := p.parseAnd()
+    if err != nil {
+        return nil, err
+    }
+    for t := p.peek(); t.kind == 'n' && t.value == "or"; t = p.peek() {
+        p.pos++
+        right, err := p.parseAnd()
+        if err != nil {
+            return nil, err
+        }
+        left = binaryNode{op: "or", left: left, right: right}
+    }
+    return left, nil
+}
+
+// parseAnd 解析and表达式
+func (p *xpathParser) parseAnd() (xpathNode, error) {
+    left, err := p.parseComparison()
+    if err != nil {
+        return nil, err
+    }
+    for t := p.peek(); t.kind == 'n' && t.value == "and"; t = p.peek() {
+        p.pos++
+        right, err := p.parseComparison()
+        if err != nil {
+            return nil, err
+        }
+        left = binaryNode{op: "and", left: left, right: right}
+    }
+    return left, nil
+}
+
+// parseComparison 解析比较表达式
+func (p *xpathParser) parseComparison() (xpathNode, error) {
+    left, err := p.parseAdditive()
+    if err != nil {
+        return nil, err
+    }
+    if t := p.peek(); t.kind == 'o' {
+        switch t.value {
+        case "=", "!=", "<", "<=", ">", ">=":
+            p.pos++
+            right, err := p.parseAdditive()
+            if err != nil {
+                return nil, err
+            }
+            return binaryNode{op: t.value, left: left, right: right}, nil
+        }
+    }
+    return left, nil
+}
+
+// parseAdditive 解析加减表达式
+func (p *xpathParser) parseAdditive() (xpathNode, error) {
+    left, err := p.parseMultiplicative()
+    if err != nil {
+        return nil, err
+    }
+    for t := p.peek(); t.kind == 'o' && (t.value == "+" || t.value == "-"); t = p.peek() {
+        p.pos++
+        right, err := p.parseMultiplicative()
+        if err != nil {
+            return nil, err
+        }
+        left = arithNode{op: t.value, left: left, right: right}
+    }
+    return left, nil
+}
+
+// parseMultiplicative 解析乘除和取模表达式
+// 操作数之后的"*"是乘号，"div"和"mod"是运算符而不是函数名
+func (p *xpathParser) parseMultiplicative() (xpathNode, error) {
+    left, err := p.parsePrimary()
+    if err != nil {
+        return nil, err
+    }
+    for t := p.peek(); (t.kind == 'o' && t.value == "*") || (t.kind == 'n' && (t.value == "div" || t.value == "mod")); t = p.peek() {
+        p.pos++
+        right, err := p.parsePrimary()
+        if err != nil {
+            return nil, err
+        }
+        left = arithNode{op: t.value, left: left, right: right}
+    }
+    return left, nil
+}
+
+// parsePrimary 解析括号表达式、属性、常量和函数调用
+func (p *xpathParser) parsePrimary() (xpathNode, error) {
+    t := p.next()
+    switch t.kind {
+    case 's':
+        return literalNode{value: t.value}, nil
+    case 'd':
+        f, err := strconv.ParseFloat(t.value, 64)
+        if err != nil {
+            return nil, p.errorf("invalid number %q", t.value)
+        }
+        return literalNode{value: f}, nil
+    case 'n':
+        return p.parseFunc(t.value)
+    case 'o':
+        switch t.value {
+        case "(":
+            inner, err := p.parseOr()
+            if err != nil {
+                return nil, err
+            }
+            if !p.accept(")") {
+                return nil, p.errorf("expected )")
+            }
+            return inner, nil
+        case "-":
+            operand, err := p.parsePrimary()
+            if err != nil {
+                return nil, err
+            }
+            return negateNode{operand: operand}, nil
+        case "@":
+            name := p.next()
+            if name.kind != 'n' {
+                return nil, p.errorf("expected attribute name after @")
+            }
+            return attrNode{name: name.value}, nil
+        }
+    }
+    if t.value == "" {
+        return nil, p.errorf("unexpected end of expression")
+    }
+    return nil, p.errorf("unexpected %q", t.value)
+}
+
+// parseFunc 解析函数调用
+func (p *xpathParser) parseFunc(name string) (xpathNode, error) {
+    arity, ok := xpathFuncArity[name]
+    if !ok {
+        return nil, p.errorf("unsupported function %s()", name)
+    }
+    if !p.accept("(") {
+        return nil, p.errorf("expected ( after %s", name)
+    }
+    fn := funcNode{name: name}
+    if !p.accept(")") {
+        for {
+            arg, err := p.parseOr()
+            if err != nil {
+                return nil, err
+            }
+            fn.args = append(fn.args, arg)
+            if p.accept(")") {
+                break
+            }
+            if !p.accept(",") {
+                return nil, p.errorf("expected , or ) in %s()", name)
+            }
+        }
+    }
+    if len(fn.args) != arity {
+        return nil, p.errorf("%s() takes %d argument(s), got %d", name, arity, len(fn.args))
+    }
+    if name == "matches" {
+        pattern, ok := fn.args[1].(literalNode)
+        if !ok {
+            return nil, p.errorf("matches() pattern must be a string literal")
+        }
+        re, err := regexp.Compile(xpathString(pattern.value))
+        if err != nil {
+            return nil, p.errorf("invalid pattern in matches(): %v", err)
+        }
+        fn.re = re
+    }
+    return fn, nil
+}
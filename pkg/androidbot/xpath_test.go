@@ -0,0 +1,186 @@
+package androidbot
+
+import (
+    "errors"
+    "strings"
+    "testing"
+)
+
+// testHierarchyXML 是测试用的界面层级
+// 节点的text或resource-id各不相同，用于在结果中辨认节点
+const testHierarchyXML = `<?xml version='1.0' encoding='UTF-8' standalone='yes' ?>
+<hierarchy rotation="1">
+  <node index="0" class="android.widget.FrameLayout" resource-id="root" bounds="[0,0][1080,2400]">
+    <node index="0" class="android.widget.LinearLayout" resource-id="row1" bounds="[0,0][1080,200]">
+      <node index="0" class="android.widget.TextView" text="A1" bounds="[0,0][360,200]" />
+      <node index="1" class="android.widget.Button" text="B1" enabled="true" bounds="[360,0][720,200]" />
+      <node index="2" class="android.widget.Button" text="B2" enabled="false" bounds="[720,0][1080,200]" />
+    </node>
+    <node index="1" class="android.widget.LinearLayout" resource-id="row2" bounds="[0,200][1080,400]">
+      <node index="0" class="android.widget.Button" text="B3" checked="true" bounds="[0,200][540,400]" />
+      <node index="1" class="android.widget.TextView" text="A2" content-desc="second" bounds="[540,200][1080,400]" />
+    </node>
+  </node>
+</hierarchy>`
+
+// nodeLabel 返回节点的text，没有text时返回resource-id，都没有时返回标签名
+func nodeLabel(n *Node) string {
+    if n.Text != "" {
+        return n.Text
+    }
+    if n.ResourceID != "" {
+        return n.ResourceID
+    }
+    return n.Tag
+}
+
+func parseTestHierarchy(t *testing.T) *Hierarchy {
+    t.Helper()
+    h, err := ParseHierarchy([]byte(testHierarchyXML))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return h
+}
+
+func TestXPathSelect(t *testing.T) {
+    h := parseTestHierarchy(t)
+    tests := []struct {
+        xpath string
+        want  string
+    }{
+        // 路径和元素名
+        {"/hierarchy", "hierarchy"},
+        {"/hierarchy/node", "root"},
+        {"//android.widget.Button", "B1,B2,B3"},
+        {"//node[@resource-id='row2']/*", "B3,A2"},
+        {"//android.widget.Button/..", "row1,row2"},
+        {"//node[@text='B3']/.", "B3"},
+        {"node/node", "row1,row2"},
+
+        // 位置谓词在每个父节点内部计数
+        {"//android.widget.Button[1]", "B1,B3"},
+        {"//node/node[1]", "row1,A1,B3"},
+        {"//android.widget.LinearLayout/*[last()]", "B2,A2"},
+        {"//android.widget.LinearLayout/*[last()-1]", "B1,B3"},
+        {"//android.widget.LinearLayout/*[position()>1]", "B1,B2,A2"},
+        {"//android.widget.LinearLayout/*[position() mod 2 = 1]", "A1,B2,B3"},
+        {"//android.widget.Button[@enabled='true'][1]", "B1"},
+
+        // 括号表达式的谓词作用于整个结果集
+        {"(//android.widget.Button)[1]", "B1"},
+        {"(//android.widget.Button)[last()]", "B3"},
+        {"(//android.widget.Button)[2]/..", "row1"},
+        {"(//node[@resource-id='row2'])[1]//android.widget.TextView", "A2"},
+
+        // 反向轴的位置从离上下文最近的节点开始
+        {"//node[@text='B2']/ancestor::*[1]", "row1"},
+        {"//node[@text='B2']/ancestor::*[2]", "root"},
+        {"//node[@text='B2']/preceding-sibling::*[1]", "B1"},
+        {"//node[@text='B2']/preceding-sibling::*[last()]", "A1"},
+        {"//node[@text='A1']/following-sibling::*[1]", "B1"},
+        {"//node[@text='B3']/ancestor-or-self::node[1]", "B3"},
+        {"//node[@resource-id='row1']/descendant::android.widget.Button", "B1,B2"},
+        {"//node[@text='A2']/parent::*", "row2"},
+        {"//node[@text='A2']/self::android.widget.TextView", "A2"},
+
+        // 多个上下文的结果按文档顺序排列并去重
+        {"//android.widget.Button/ancestor::*", "hierarchy,root,row1,row2"},
+        {"//node[@text='B3' or @text='A1']", "A1,B3"},
+        {"//node[@text='B2']/preceding-sibling::*", "A1,B1"},
+
+        // 函数和运算符
+        {"//node[not(@checked)][@text='B3' or @text='A2']", "A2"},
+        {"//node[not(@checked='true')][starts-with(@text,'B')]", "B1,B2"},
+        {"//node[@content-desc!='second']", ""},
+        {"//node[@checked!='false']", "B3"},
+        {"//node[not(@content-desc='second')][@text='A2']", ""},
+        {"//node[contains(text(),'2')]", "B2,A2"},
+        {"//node[ends-with(@resource-id,'2')]", "row2"},
+        {"//node[matches(@text,'^B[13]$')]", "B1,B3"},
+        {"//node[string-length(@text)=2][@index=-1+2]", "B1,A2"},
+        {"//node[normalize-space(@text)='A1']", "A1"},
+        {"//node[@index>=1 and @index<2]", "B1,row2,A2"},
+        {"//node[@index * 2 = 4]", "B2"},
+        {"//node[@index div 2 = 1]", "B2"},
+        {"//node[@index=-1]", ""},
+        {"//android.widget.Button[5]", ""},
+    }
+    for _, tt := range tests {
+        nodes, err := h.FindAll(tt.xpath)
+        if err != nil {
+            t.Errorf("FindAll(%q): %v", tt.xpath, err)
+            continue
+        }
+        labels := make([]string, len(nodes))
+        for i, n := range nodes {
+            labels[i] = nodeLabel(n)
+        }
+        if got := strings.Join(labels, ","); got != tt.want {
+            t.Errorf("FindAll(%q) = %q, want %q", tt.xpath, got, tt.want)
+        }
+    }
+}
+
+func TestXPathRelativeToNode(t *testing.T) {
+    h := parseTestHierarchy(t)
+    row, err := h.Find("//node[@resource-id='row2']")
+    if err != nil {
+        t.Fatal(err)
+    }
+    // 相对表达式以节点为上下文，绝对表达式仍然从根开始
+    if n, err := row.Find("android.widget.TextView"); err != nil || n.Text != "A2" {
+        t.Errorf("relative Find = %v, %v", n, err)
+    }
+    if n, err := row.Find("//android.widget.TextView"); err != nil || n.Text != "A1" {
+        t.Errorf("absolute Find from node = %v, %v", n, err)
+    }
+    if _, err := row.Find("android.widget.EditText"); !errors.Is(err, ErrNodeNotFound) {
+        t.Errorf("Find without match = %v, want ErrNodeNotFound", err)
+    }
+}
+
+func TestXPathInvalid(t *testing.T) {
+    for _, xpath := range []string{
+        "",
+        "//",
+        "//node[",
+        "//node[@text='a'",
+        "//node[@text='a]",
+        "(//node",
+        "//node | //node",
+        "//node/following::*",
+        "//node[unknown()]",
+        "//node[contains(@text)]",
+        "//node[matches(@text, @pattern)]",
+        "//node[matches(@text, '(')]",
+        "//node#",
+    } {
+        if _, err := compileXPath(xpath); err == nil {
+            t.Errorf("compileXPath(%q) should fail", xpath)
+        }
+    }
+}
+
+func TestParseHierarchy(t *testing.T) {
+    h := parseTestHierarchy(t)
+    if h.Rotation != 1 {
+        t.Errorf("Rotation = %d, want 1", h.Rotation)
+    }
+    n, err := h.Find("//node[@text='B3']")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !n.Checked || n.Class != "android.widget.Button" || n.Parent.ResourceID != "row2" {
+        t.Errorf("node = %+v", n)
+    }
+    if x, y := n.Center(); x != 270 || y != 300 {
+        t.Errorf("Center = (%d, %d), want (270, 300)", x, y)
+    }
+
+    for _, data := range []string{"", "<hierarchy>", `<a/><b/>`, `<node bounds="[0,0]"/>`} {
+        if _, err := ParseHierarchy([]byte(data)); err == nil {
+            t.Errorf("ParseHierarchy(%q) should fail", data)
+        }
+    }
+}
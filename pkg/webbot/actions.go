@@ -0,0 +1,358 @@
+package webbot
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+)
+
+// Key 表示键盘上的一个按键
+// 普通字符直接使用字符本身，如Key("a")；特殊按键使用下列常量
+// 常量的值是W3C WebDriver规范定义的按键码位
+type Key string
+
+const (
+    KeyCancel     Key = "\uE001"
+    KeyHelp       Key = "\uE002"
+    KeyBackspace  Key = "\uE003"
+    KeyTab        Key = "\uE004"
+    KeyClear      Key = "\uE005"
+    KeyReturn     Key = "\uE006"
+    KeyEnter      Key = "\uE007"
+    KeyShift      Key = "\uE008"
+    KeyControl    Key = "\uE009"
+    KeyAlt        Key = "\uE00A"
+    KeyPause      Key = "\uE00B"
+    KeyEscape     Key = "\uE00C"
+    KeySpace      Key = "\uE00D"
+    KeyPageUp     Key = "\uE00E"
+    KeyPageDown   Key = "\uE00F"
+    KeyEnd        Key = "\uE010"
+    KeyHome       Key = "\uE011"
+    KeyArrowLeft  Key = "\uE012"
+    KeyArrowUp    Key = "\uE013"
+    KeyArrowRight Key = "\uE014"
+    KeyArrowDown  Key = "\uE015"
+    KeyInsert     Key = "\uE016"
+    KeyDelete     Key = "\uE017"
+    KeyF1         Key = "\uE031"
+    KeyF2         Key = "\uE032"
+    KeyF3         Key = "\uE033"
+    KeyF4         Key = "\uE034"
+    KeyF5         Key = "\uE035"
+    KeyF6         Key = "\uE036"
+    KeyF7         Key = "\uE037"
+    KeyF8         Key = "\uE038"
+    KeyF9         Key = "\uE039"
+    KeyF10        Key = "\uE03A"
+    KeyF11        Key = "\uE03B"
+    KeyF12        Key = "\uE03C"
+    KeyMeta       Key = "\uE03D"
+)
+
+// MouseButton 表示鼠标按键
+type MouseButton int
+
+const (
+    MouseLeft   MouseButton = 0
+    MouseMiddle MouseButton = 1
+    MouseRight  MouseButton = 2
+)
+
+// dragStepDuration 是拖拽过程中每次移动的持续时间
+// 带有持续时间的移动会产生连续的mousemove事件，拖拽库依赖这些事件识别拖拽
+const dragStepDuration = 100 * time.Millisecond
+
+// 输入源ID，与W3C actions中的source id对应
+const (
+    sourcePointer  = "mouse"
+    sourceKeyboard = "keyboard"
+    sourceWheel    = "wheel"
+)
+
+// actionOrigin 表示指针和滚轮动作的坐标原点
+// 为nil时表示视口原点；pointer为true时表示相对于指针当前位置；否则相对于元素中心
+type actionOrigin struct {
+    pointer bool
+    element By
+}
+
+// originValue 将原点转换为W3C actions的origin字段
+// 元素原点以定位器文本表示，由驱动程序在执行时查找元素
+func originValue(o *actionOrigin) interface{} {
+    if o == nil {
+        return "viewport"
+    }
+    if o.pointer {
+        return "pointer"
+    }
+    return map[string]string{"locator": o.element.String()}
+}
+
+// action 是一个输入源在一个时间片内执行的动作
+type action struct {
+    Type     string
+    Duration int64
+    Origin   *actionOrigin
+    X        int
+    Y        int
+    DeltaX   int
+    DeltaY   int
+    Button   MouseButton
+    Value    Key
+}
+
+// actionStep 表示动作序列中的一个时间片
+type actionStep struct {
+    source string
+    action action
+}
+
+// actionSource 是W3C actions中一个输入源的动作列表
+type actionSource struct {
+    Type       string            `json:"type"`
+    ID         string            `json:"id"`
+    Parameters map[string]string `json:"parameters,omitempty"`
+    Actions    []json.RawMessage `json:"actions"`
+}
+
+// Actions 是W3C actions风格的鼠标、键盘和滚轮动作构建器
+// 方法可以链式调用，最后调用Perform一次性执行整个序列
+// 执行期间其他动作序列不会插入，因此拖拽、悬停菜单等复合操作不会被打断
+// 例如：bot.Actions().KeyDown(webbot.KeyShift).Click().KeyUp(webbot.KeyShift).Perform()
+type Actions struct {
+    bot   WebBot
+    steps []actionStep
+    err   error
+}
+
+// NewActions 创建一个绑定到bot的动作构建器
+func NewActions(bot WebBot) *Actions {
+    return &Actions{bot: bot}
+}
+
+// add 追加一个时间片
+func (a *Actions) add(source string, act action) *Actions {
+    a.steps = append(a.steps, actionStep{source: source, action: act})
+    return a
+}
+
+// elementOrigin 返回元素原点，并记录定位器错误
+func (a *Actions) elementOrigin(by By) *actionOrigin {
+    if err := by.validate(); err != nil && a.err == nil {
+        a.err = err
+    }
+    return &actionOrigin{element: by}
+}
+
+// MoveTo 将鼠标移动到视口坐标(x, y)
+func (a *Actions) MoveTo(x, y int) *Actions {
+    return a.add(sourcePointer, action{Type: "pointerMove", X: x, Y: y})
+}
+
+// MoveByOffset 将鼠标从当前位置移动(dx, dy)
+func (a *Actions) MoveByOffset(dx, dy int) *Actions {
+    return a.add(sourcePointer, action{Type: "pointerMove", Origin: &actionOrigin{pointer: true}, X: dx, Y: dy})
+}
+
+// MoveToElement 将鼠标移动到元素中心偏移(offsetX, offsetY)的位置
+func (a *Actions) MoveToElement(by By, offsetX, offsetY int) *Actions {
+    return a.add(sourcePointer, action{Type: "pointerMove", Origin: a.elementOrigin(by), X: offsetX, Y: offsetY})
+}
+
+// Hover 将鼠标悬停在元素中心
+func (a *Actions) Hover(by By) *Actions {
+    return a.MoveToElement(by, 0, 0)
+}
+
+// MouseDown 在当前位置按下鼠标按键
+func (a *Actions) MouseDown(button MouseButton) *Actions {
+    return a.add(sourcePointer, action{Type: "pointerDown", Button: button})
+}
+
+// MouseUp 在当前位置释放鼠标按键
+func (a *Actions) MouseUp(button MouseButton) *Actions {
+    return a.add(sourcePointer, action{Type: "pointerUp", Button: button})
+}
+
+// Click 在当前位置单击鼠标左键
+func (a *Actions) Click() *Actions {
+    return a.MouseDown(MouseLeft).MouseUp(MouseLeft)
+}
+
+// ClickElement 移动到元素中心并单击鼠标左键
+func (a *Actions) ClickElement(by By) *Actions {
+    return a.Hover(by).Click()
+}
+
+// DoubleClick 移动到元素中心并双击鼠标左键
+func (a *Actions) DoubleClick(by By) *Actions {
+    return a.Hover(by).Click().Click()
+}
+
+// ContextClick 移动到元素中心并单击鼠标右键，通常会打开右键菜单
+func (a *Actions) ContextClick(by By) *Actions {
+    return a.Hover(by).MouseDown(MouseRight).MouseUp(MouseRight)
+}
+
+// DragAndDrop 将source元素拖拽到target元素上释放
+// 拖拽过程分多次移动，以触发依赖mousemove事件的拖拽库
+func (a *Actions) DragAndDrop(source, target By) *Actions {
+    a.Hover(source).MouseDown(MouseLeft)
+    // 先小幅移动，越过拖拽库的启动阈值
+    a.add(sourcePointer, action{Type: "pointerMove", Origin: &actionOrigin{pointer: true}, X: 5, Y: 5, Duration: dragStepDuration.Milliseconds()})
+    a.add(sourcePointer, action{Type: "pointerMove", Origin: a.elementOrigin(target), Duration: dragStepDuration.Milliseconds()})
+    return a.MouseUp(MouseLeft)
+}
+
+// DragAndDropBy 将source元素拖拽(dx, dy)后释放，适用于滑块等控件
+func (a *Actions) DragAndDropBy(source By, dx, dy int) *Actions {
+    a.Hover(source).MouseDown(MouseLeft)
+    a.add(sourcePointer, action{Type: "pointerMove", Origin: &actionOrigin{pointer: true}, X: dx, Y: dy, Duration: dragStepDuration.Milliseconds()})
+    return a.MouseUp(MouseLeft)
+}
+
+// KeyDown 按下一个按键，通常用于按住修饰键
+func (a *Actions) KeyDown(key Key) *Actions {
+    return a.add(sourceKeyboard, action{Type: "keyDown", Value: key})
+}
+
+// KeyUp 释放一个按键
+func (a *Actions) KeyUp(key Key) *Actions {
+    return a.add(sourceKeyboard, action{Type: "keyUp", Value: key})
+}
+
+// KeyChord 依次按下所有按键后按相反顺序释放
+// 例如：KeyChord(webbot.KeyControl, "a")表示Ctrl+A
+func (a *Actions) KeyChord(keys ...Key) *Actions {
+    for _, key := range keys {
+        a.KeyDown(key)
+    }
+    for i := len(keys) - 1; i >= 0; i-- {
+        a.KeyUp(keys[i])
+    }
+    return a
+}
+
+// SendKeys 逐个字符输入文本
+func (a *Actions) SendKeys(text string) *Actions {
+    for _, r := range text {
+        key := Key(string(r))
+        a.KeyDown(key).KeyUp(key)
+    }
+    return a
+}
+
+// ScrollBy 在视口左上角滚动鼠标滚轮(dx, dy)像素
+func (a *Actions) ScrollBy(dx, dy int) *Actions {
+    return a.add(sourceWheel, action{Type: "scroll", DeltaX: dx, DeltaY: dy})
+}
+
+// ScrollFromElement 在元素中心滚动鼠标滚轮(dx, dy)像素，适用于内部可滚动的容器
+func (a *Actions) ScrollFromElement(by By, dx, dy int) *Actions {
+    return a.add(sourceWheel, action{Type: "scroll", Origin: a.elementOrigin(by), DeltaX: dx, DeltaY: dy})
+}
+
+// Pause 在序列中等待指定时间
+func (a *Actions) Pause(d time.Duration) *Actions {
+    return a.add(sourcePointer, action{Type: "pause", Duration: d.Milliseconds()})
+}
+
+// Perform 一次性执行构建的动作序列
+// 序列中的定位器错误在这里返回，此时不会执行任何动作
+func (a *Actions) Perform() error {
+    if a.err != nil {
+        return a.err
+    }
+    if a.bot == nil {
+        return errors.New("webbot: actions are not bound to a bot, use NewActions or WebBot.Actions")
+    }
+    return a.bot.PerformActions(a)
+}
+
+// MarshalJSON 将动作序列编码为W3C actions格式
+// 每个时间片只有一个输入源执行动作，其他输入源以pause补齐
+func (a *Actions) MarshalJSON() ([]byte, error) {
+    sources := []*actionSource{
+        {Type: "pointer", ID: sourcePointer, Parameters: map[string]string{"pointerType": "mouse"}},
+        {Type: "key", ID: sourceKeyboard},
+        {Type: "wheel", ID: sourceWheel},
+    }
+    pause := json.RawMessage(`{"type":"pause"}`)
+    for _, step := range a.steps {
+        for _, src := range sources {
+            if src.ID != step.source {
+                src.Actions = append(src.Actions, pause)
+                continue
+            }
+            data, err := encodeAction(step.action)
+            if err != nil {
+                return nil, err
+            }
+            src.Actions = append(src.Actions, data)
+        }
+    }
+    return json.Marshal(map[string]interface{}{"actions": sources})
+}
+
+// encodeAction 只保留动作类型需要的字段
+func encodeAction(act action) (json.RawMessage, error) {
+    fields := map[string]interface{}{"type": act.Type}
+    switch act.Type {
+    case "pause":
+        fields["duration"] = act.Duration
+    case "pointerMove":
+        fields["x"], fields["y"], fields["duration"] = act.X, act.Y, act.Duration
+        fields["origin"] = originValue(act.Origin)
+    case "pointerDown", "pointerUp":
+        fields["button"] = act.Button
+    case "keyDown", "keyUp":
+        fields["value"] = act.Value
+    case "scroll":
+        fields["x"], fields["y"], fields["duration"] = act.X, act.Y, act.Duration
+        fields["deltaX"], fields["deltaY"] = act.DeltaX, act.DeltaY
+        fields["origin"] = originValue(act.Origin)
+    default:
+        return nil, fmt.Errorf("webbot: unknown action type %q", act.Type)
+    }
+    return json.Marshal(fields)
+}
+
+// 实现WebBot接口的Actions方法
+func (b *webBotImpl) Actions() *Actions {
+    return NewActions(b)
+}
+
+// 实现WebBot接口的PerformActions方法
+func (b *webBotImpl) PerformActions(actions *Actions) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if actions == nil || len(actions.steps) == 0 {
+        return nil
+    }
+    if actions.err != nil {
+        return actions.err
+    }
+    payload, err := json.Marshal(actions)
+    if err != nil {
+        return fmt.Errorf("failed to encode actions: %w", err)
+    }
+    // 同一时间只执行一个动作序列，避免并发的序列互相穿插
+    b.actionsMu.Lock()
+    defer b.actionsMu.Unlock()
+    // 实际实现将在后续添加，payload将作为一条命令发送给驱动程序
+    // 这里返回nil作为占位符
+    _ = payload
+    return nil
+}
+
+// 实现WebBot接口的ReleaseActions方法
+func (b *webBotImpl) ReleaseActions() error {
+    b.actionsMu.Lock()
+    defer b.actionsMu.Unlock()
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
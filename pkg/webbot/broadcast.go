@@ -0,0 +1,64 @@
+package webbot
+
+import "sync"
+
+// subscriberBuffer 是每个事件订阅通道的缓冲大小
+// 订阅者处理过慢时，超出缓冲的事件会被丢弃，避免阻塞驱动事件循环
+const subscriberBuffer = 256
+
+// broadcaster 将驱动程序上报的事件分发给多个订阅者
+// 它被网络、控制台、CDP等事件流共用
+type broadcaster[T any] struct {
+    mu     sync.Mutex
+    nextID int
+    subs   map[int]chan T
+}
+
+// subscribe 注册一个新的订阅者
+// 返回只读事件通道和取消订阅函数，取消后通道会被关闭
+func (bc *broadcaster[T]) subscribe() (<-chan T, func()) {
+    bc.mu.Lock()
+    defer bc.mu.Unlock()
+    if bc.subs == nil {
+        bc.subs = map[int]chan T{}
+    }
+    id := bc.nextID
+    bc.nextID++
+    ch := make(chan T, subscriberBuffer)
+    bc.subs[id] = ch
+
+    var once sync.Once
+    cancel := func() {
+        once.Do(func() {
+            bc.mu.Lock()
+            defer bc.mu.Unlock()
+            if sub, ok := bc.subs[id]; ok {
+                delete(bc.subs, id)
+                close(sub)
+            }
+        })
+    }
+    return ch, cancel
+}
+
+// publish 将事件发送给所有订阅者，不会阻塞
+func (bc *broadcaster[T]) publish(event T) {
+    bc.mu.Lock()
+    defer bc.mu.Unlock()
+    for _, ch := range bc.subs {
+        select {
+        case ch <- event:
+        default:
+        }
+    }
+}
+
+// closeAll 关闭所有订阅通道，在停止服务时调用
+func (bc *broadcaster[T]) closeAll() {
+    bc.mu.Lock()
+    defer bc.mu.Unlock()
+    for id, ch := range bc.subs {
+        delete(bc.subs, id)
+        close(ch)
+    }
+}
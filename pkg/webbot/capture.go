@@ -0,0 +1,175 @@
+package webbot
+
+import (
+    "bytes"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "image"
+    "image/png"
+)
+
+// ErrEmptyCapture 表示驱动程序没有返回任何截图或PDF数据
+var ErrEmptyCapture = errors.New("webbot: driver returned empty capture data")
+
+// PaperSize 表示PDF纸张尺寸，单位为英寸
+type PaperSize struct {
+    Width  float64
+    Height float64
+}
+
+var (
+    PaperA3     = PaperSize{Width: 11.69, Height: 16.54}
+    PaperA4     = PaperSize{Width: 8.27, Height: 11.69}
+    PaperA5     = PaperSize{Width: 5.83, Height: 8.27}
+    PaperLetter = PaperSize{Width: 8.5, Height: 11}
+    PaperLegal  = PaperSize{Width: 8.5, Height: 14}
+)
+
+// Margins 表示PDF页边距，单位为英寸
+type Margins struct {
+    Top    float64
+    Bottom float64
+    Left   float64
+    Right  float64
+}
+
+// PDFOptions 表示打印PDF的选项
+// Paper: 纸张尺寸，零值表示使用PaperA4
+// Margins: 页边距
+// Landscape: 是否横向打印
+// PrintBackground: 是否打印背景图形
+// Scale: 缩放比例，零值表示1.0，取值范围0.1-2
+// PageRanges: 打印的页码范围，如"1-3,5"，为空表示全部页面
+type PDFOptions struct {
+    Paper           PaperSize
+    Margins         Margins
+    Landscape       bool
+    PrintBackground bool
+    Scale           float64
+    PageRanges      string
+}
+
+// params 将选项转换为发送给驱动程序的参数，并填充默认值
+func (o PDFOptions) params() (map[string]interface{}, error) {
+    paper := o.Paper
+    if paper == (PaperSize{}) {
+        paper = PaperA4
+    }
+    scale := o.Scale
+    if scale == 0 {
+        scale = 1
+    }
+    if scale < 0.1 || scale > 2 {
+        return nil, fmt.Errorf("invalid pdf scale %v: must be between 0.1 and 2", scale)
+    }
+    if paper.Width <= 0 || paper.Height <= 0 {
+        return nil, fmt.Errorf("invalid paper size %vx%v", paper.Width, paper.Height)
+    }
+    return map[string]interface{}{
+        "paperWidth":      paper.Width,
+        "paperHeight":     paper.Height,
+        "marginTop":       o.Margins.Top,
+        "marginBottom":    o.Margins.Bottom,
+        "marginLeft":      o.Margins.Left,
+        "marginRight":     o.Margins.Right,
+        "landscape":       o.Landscape,
+        "printBackground": o.PrintBackground,
+        "scale":           scale,
+        "pageRanges":      o.PageRanges,
+    }, nil
+}
+
+// decodeCapture 解码驱动程序以base64返回的截图或PDF数据
+func decodeCapture(data string) ([]byte, error) {
+    if data == "" {
+        return nil, ErrEmptyCapture
+    }
+    raw, err := base64.StdEncoding.DecodeString(data)
+    if err != nil {
+        return nil, fmt.Errorf("failed to decode capture data: %w", err)
+    }
+    return raw, nil
+}
+
+// decodePNG 将PNG字节解码为image.Image
+func decodePNG(data []byte) (image.Image, error) {
+    img, err := png.Decode(bytes.NewReader(data))
+    if err != nil {
+        return nil, fmt.Errorf("failed to decode png: %w", err)
+    }
+    return img, nil
+}
+
+// capture 向驱动程序请求截图或PDF数据，返回base64编码的字符串
+// kind: "viewport"、"fullPage"、"element"或"pdf"
+func (b *webBotImpl) capture(kind string, params map[string]interface{}) (string, error) {
+    // 实际实现将在后续添加
+    // 这里返回空字符串和nil作为占位符
+    return "", nil
+}
+
+// 实现WebBot接口的Screenshot方法
+func (b *webBotImpl) Screenshot(fullPage bool) ([]byte, error) {
+    if err := b.checkPageError(); err != nil {
+        return nil, err
+    }
+    kind := "viewport"
+    if fullPage {
+        kind = "fullPage"
+    }
+    data, err := b.capture(kind, nil)
+    if err != nil {
+        return nil, err
+    }
+    return decodeCapture(data)
+}
+
+// 实现WebBot接口的ScreenshotImage方法
+func (b *webBotImpl) ScreenshotImage(fullPage bool) (image.Image, error) {
+    data, err := b.Screenshot(fullPage)
+    if err != nil {
+        return nil, err
+    }
+    return decodePNG(data)
+}
+
+// 实现WebBot接口的ElementScreenshot方法
+func (b *webBotImpl) ElementScreenshot(by By) ([]byte, error) {
+    if err := b.checkPageError(); err != nil {
+        return nil, err
+    }
+    if err := by.validate(); err != nil {
+        return nil, err
+    }
+    data, err := b.capture("element", map[string]interface{}{"locator": by.String()})
+    if err != nil {
+        return nil, err
+    }
+    return decodeCapture(data)
+}
+
+// 实现WebBot接口的ElementScreenshotImage方法
+func (b *webBotImpl) ElementScreenshotImage(by By) (image.Image, error) {
+    data, err := b.ElementScreenshot(by)
+    if err != nil {
+        return nil, err
+    }
+    return decodePNG(data)
+}
+
+// 实现WebBot接口的PrintToPDF方法
+func (b *webBotImpl) PrintToPDF(options PDFOptions) ([]byte, error) {
+    if err := b.checkPageError(); err != nil {
+        return nil, err
+    }
+    params, err := options.params()
+    if err != nil {
+        return nil, err
+    }
+    data, err := b.capture("pdf", params)
+    if err != nil {
+        return nil, err
+    }
+    return decodeCapture(data)
+}
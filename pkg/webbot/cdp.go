@@ -0,0 +1,311 @@
+package webbot
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// cdpTimeout 是CDP命令等待响应的默认超时时间
+const cdpTimeout = 30 * time.Second
+
+// ErrCDPClosed 表示CDP连接已经关闭
+var ErrCDPClosed = errors.New("webbot: cdp connection closed")
+
+// CDPError 表示浏览器对CDP命令返回的错误
+type CDPError struct {
+    Method  string
+    Code    int    `json:"code"`
+    Message string `json:"message"`
+    Data    string `json:"data"`
+}
+
+func (e *CDPError) Error() string {
+    msg := fmt.Sprintf("webbot: cdp %s failed: %s (%d)", e.Method, e.Message, e.Code)
+    if e.Data != "" {
+        msg += ": " + e.Data
+    }
+    return msg
+}
+
+// CDPEvent 表示浏览器推送的一个CDP事件
+// Method: 事件名称，如"Page.loadEventFired"
+// Params: 事件参数的原始JSON
+type CDPEvent struct {
+    Method string
+    Params json.RawMessage
+}
+
+// cdpMessage 是CDP协议中命令、响应和事件共用的消息格式
+type cdpMessage struct {
+    ID     int64           `json:"id,omitempty"`
+    Method string          `json:"method,omitempty"`
+    Params json.RawMessage `json:"params,omitempty"`
+    Result json.RawMessage `json:"result,omitempty"`
+    Error  *CDPError       `json:"error,omitempty"`
+}
+
+// cdpTarget 是调试端口/json/list返回的调试目标
+type cdpTarget struct {
+    Type                 string `json:"type"`
+    URL                  string `json:"url"`
+    WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+// cdpClient 是连接到页面调试目标的CDP客户端
+type cdpClient struct {
+    ws     *wsConn
+    events broadcaster[CDPEvent]
+
+    mu      sync.Mutex
+    nextID  int64
+    pending map[int64]chan cdpMessage
+    closed  bool
+    done    chan struct{}
+}
+
+// findPageTarget 通过调试端口查找第一个页面类型的调试目标
+func findPageTarget(port int) (string, error) {
+    client := http.Client{Timeout: 5 * time.Second}
+    resp, err := client.Get("http://127.0.0.1:" + strconv.Itoa(port) + "/json/list")
+    if err != nil {
+        return "", fmt.Errorf("failed to list devtools targets: %w", err)
+    }
+    defer resp.Body.Close()
+    var targets []cdpTarget
+    if err := json.NewDecoder(resp.Body).Decode(&targets); err != nil {
+        return "", fmt.Errorf("failed to parse devtools targets: %w", err)
+    }
+    for _, target := range targets {
+        if target.Type == "page" && target.WebSocketDebuggerURL != "" {
+            return target.WebSocketDebuggerURL, nil
+        }
+    }
+    return "", errors.New("webbot: no page target found on debug port")
+}
+
+// dialCDP 连接到CDP WebSocket地址并开始读取消息
+func dialCDP(wsURL string) (*cdpClient, error) {
+    ws, err := dialWebSocket(wsURL, 10*time.Second)
+    if err != nil {
+        return nil, err
+    }
+    c := &cdpClient{
+        ws:      ws,
+        pending: map[int64]chan cdpMessage{},
+        done:    make(chan struct{}),
+    }
+    go c.readLoop()
+    return c, nil
+}
+
+// readLoop 读取浏览器消息，将响应交给等待中的命令，将事件分发给订阅者
+func (c *cdpClient) readLoop() {
+    defer c.shutdown()
+    for {
+        data, err := c.ws.readMessage()
+        if err != nil {
+            return
+        }
+        var msg cdpMessage
+        if err := json.Unmarshal(data, &msg); err != nil {
+            continue
+        }
+        if msg.ID != 0 {
+            c.mu.Lock()
+            ch, ok := c.pending[msg.ID]
+            delete(c.pending, msg.ID)
+            c.mu.Unlock()
+            if ok {
+                ch <- msg
+            }
+            continue
+        }
+        if msg.Method != "" {
+            c.events.publish(CDPEvent{Method: msg.Method, Params: msg.Params})
+        }
+    }
+}
+
+// shutdown 标记连接关闭，唤醒所有等待中的命令并关闭事件订阅
+func (c *cdpClient) shutdown() {
+    c.mu.Lock()
+    if c.closed {
+        c.mu.Unlock()
+        return
+    }
+    c.closed = true
+    // 先关闭done，被唤醒的命令重试时cdpConn能够发现连接已断开
+    close(c.done)
+    for id, ch := range c.pending {
+        delete(c.pending, id)
+        close(ch)
+    }
+    c.mu.Unlock()
+    c.events.closeAll()
+}
+
+// send 发送一个CDP命令并等待响应
+func (c *cdpClient) send(method string, params interface{}, timeout time.Duration) (json.RawMessage, error) {
+    var raw json.RawMessage
+    if params != nil {
+        data, err := json.Marshal(params)
+        if err != nil {
+            return nil, fmt.Errorf("failed to encode cdp params: %w", err)
+        }
+        raw = data
+    }
+
+    c.mu.Lock()
+    if c.closed {
+        c.mu.Unlock()
+        return nil, ErrCDPClosed
+    }
+    c.nextID++
+    id := c.nextID
+    ch := make(chan cdpMessage, 1)
+    c.pending[id] = ch
+    c.mu.Unlock()
+
+    data, err := json.Marshal(cdpMessage{ID: id, Method: method, Params: raw})
+    if err != nil {
+        return nil, err
+    }
+    if err := c.ws.writeText(data); err != nil {
+        c.mu.Lock()
+        delete(c.pending, id)
+        c.mu.Unlock()
+        return nil, fmt.Errorf("failed to send cdp command: %w", err)
+    }
+
+    timer := time.NewTimer(timeout)
+    defer timer.Stop()
+    select {
+    case msg, ok := <-ch:
+        if !ok {
+            return nil, ErrCDPClosed
+        }
+        if msg.Error != nil {
+            msg.Error.Method = method
+            return nil, msg.Error
+        }
+        if msg.Result == nil {
+            return json.RawMessage("{}"), nil
+        }
+        return msg.Result, nil
+    case <-timer.C:
+        c.mu.Lock()
+        delete(c.pending, id)
+        c.mu.Unlock()
+        return nil, fmt.Errorf("webbot: cdp %s timed out after %s", method, timeout)
+    }
+}
+
+// close 关闭CDP连接
+func (c *cdpClient) close() error {
+    err := c.ws.close()
+    <-c.done
+    return err
+}
+
+// isClosed 判断连接是否已经断开
+func (c *cdpClient) isClosed() bool {
+    select {
+    case <-c.done:
+        return true
+    default:
+        return false
+    }
+}
+
+// cdpConn 返回当前的CDP连接，未连接时通过调试端口建立连接
+// 查找调试目标和握手可能耗时数秒，期间不持有b.mu，避免阻塞弹窗、路由等事件处理
+func (b *webBotImpl) cdpConn() (*cdpClient, error) {
+    b.mu.Lock()
+    if b.cdp != nil && !b.cdp.isClosed() {
+        client := b.cdp
+        b.mu.Unlock()
+        return client, nil
+    }
+    browserName, debugPort := b.browserName, b.debugPort
+    b.mu.Unlock()
+
+    if browserName != BrowserChrome && browserName != BrowserEdge {
+        return nil, fmt.Errorf("webbot: cdp is not supported for browser %s", browserName)
+    }
+    if debugPort == 0 {
+        return nil, errors.New("webbot: debug port is unknown, start the bot first")
+    }
+    wsURL, err := findPageTarget(debugPort)
+    if err != nil {
+        return nil, err
+    }
+    client, err := dialCDP(wsURL)
+    if err != nil {
+        return nil, fmt.Errorf("failed to connect to devtools: %w", err)
+    }
+
+    b.mu.Lock()
+    if b.cdp != nil && !b.cdp.isClosed() {
+        // 其他协程已经先建立了连接，使用已有的连接并关闭自己的
+        existing := b.cdp
+        b.mu.Unlock()
+        client.close()
+        return existing, nil
+    }
+    b.cdp = client
+    b.mu.Unlock()
+    return client, nil
+}
+
+// closeCDP 关闭CDP连接，在停止服务时调用
+func (b *webBotImpl) closeCDP() error {
+    b.mu.Lock()
+    client := b.cdp
+    b.cdp = nil
+    b.mu.Unlock()
+    if client == nil {
+        return nil
+    }
+    return client.close()
+}
+
+// 实现WebBot接口的CDPSend方法
+func (b *webBotImpl) CDPSend(method string, params interface{}) (json.RawMessage, error) {
+    client, err := b.cdpConn()
+    if err != nil {
+        return nil, err
+    }
+    return client.send(method, params, cdpTimeout)
+}
+
+// 实现WebBot接口的SubscribeCDP方法
+func (b *webBotImpl) SubscribeCDP(method string) (<-chan CDPEvent, func(), error) {
+    client, err := b.cdpConn()
+    if err != nil {
+        return nil, nil, err
+    }
+    events, cancel := client.events.subscribe()
+    if method == "" {
+        return events, cancel, nil
+    }
+    // 只转发指定名称的事件，取消订阅后events关闭，转发协程随之退出
+    filtered := make(chan CDPEvent, subscriberBuffer)
+    go func() {
+        defer close(filtered)
+        for event := range events {
+            if event.Method != method {
+                continue
+            }
+            select {
+            case filtered <- event:
+            default:
+            }
+        }
+    }()
+    return filtered, cancel, nil
+}
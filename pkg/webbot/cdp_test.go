@@ -0,0 +1,288 @@
+package webbot
+
+import (
+    "encoding/json"
+    "errors"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// devToolsStandIn 是模拟浏览器调试端口的测试服务器
+// /json/list返回一个页面目标，WebSocket连接按方法名回复CDP命令
+type devToolsStandIn struct {
+    t          *testing.T
+    srv        *httptest.Server
+    handshakes atomic.Int32
+    // listing非nil时，/json/list在返回前通知listing并等待release
+    listing chan struct{}
+    release chan struct{}
+}
+
+func newDevToolsStandIn(t *testing.T) *devToolsStandIn {
+    d := &devToolsStandIn{t: t}
+    mux := http.NewServeMux()
+    mux.HandleFunc("/json/list", func(w http.ResponseWriter, r *http.Request) {
+        if d.listing != nil {
+            d.listing <- struct{}{}
+            <-d.release
+        }
+        ws := "ws://" + r.Host + "/devtools/page/1"
+        json.NewEncoder(w).Encode([]cdpTarget{
+            {Type: "service_worker", URL: "https://example.com/sw.js", WebSocketDebuggerURL: "ws://" + r.Host + "/devtools/sw"},
+            {Type: "page", URL: "about:blank", WebSocketDebuggerURL: ws},
+        })
+    })
+    mux.HandleFunc("/devtools/page/1", func(w http.ResponseWriter, r *http.Request) {
+        p := upgradeWebSocket(t, w, r)
+        if p == nil {
+            return
+        }
+        defer p.conn.Close()
+        d.handshakes.Add(1)
+        d.serve(p)
+    })
+    d.srv = httptest.NewServer(mux)
+    t.Cleanup(d.srv.Close)
+    return d
+}
+
+// port 返回服务器监听的端口，作为浏览器调试端口使用
+func (d *devToolsStandIn) port() int {
+    return d.srv.Listener.Addr().(*net.TCPAddr).Port
+}
+
+// serve 回复CDP命令直到连接关闭
+func (d *devToolsStandIn) serve(p *wsPeer) {
+    var writeMu sync.Mutex
+    send := func(v interface{}) {
+        data, _ := json.Marshal(v)
+        writeMu.Lock()
+        defer writeMu.Unlock()
+        p.writeFrame(true, wsOpText, data)
+    }
+    for {
+        f, err := p.readFrame()
+        if err != nil || f.opcode == wsOpClose {
+            return
+        }
+        var cmd struct {
+            ID     int64           `json:"id"`
+            Method string          `json:"method"`
+            Params json.RawMessage `json:"params"`
+        }
+        if err := json.Unmarshal(f.payload, &cmd); err != nil {
+            d.t.Errorf("invalid cdp command %q: %v", f.payload, err)
+            return
+        }
+        switch cmd.Method {
+        case "Browser.getVersion":
+            send(map[string]interface{}{"id": cmd.ID, "result": map[string]string{"product": "HeadlessChrome/120.0"}})
+        case "Runtime.evaluate":
+            // 原样返回参数，验证参数编码
+            send(map[string]interface{}{"id": cmd.ID, "result": json.RawMessage(cmd.Params)})
+        case "Page.enable":
+            // 事件先于响应到达，订阅者应该在命令返回前收到事件
+            send(map[string]interface{}{"method": "Page.loadEventFired", "params": map[string]float64{"timestamp": 1}})
+            send(map[string]interface{}{"method": "Network.requestWillBeSent", "params": map[string]string{"requestId": "1"}})
+            send(map[string]interface{}{"method": "Page.domContentEventFired", "params": map[string]float64{"timestamp": 2}})
+            send(map[string]interface{}{"id": cmd.ID})
+        case "Page.crash":
+            return
+        default:
+            send(map[string]interface{}{"id": cmd.ID, "error": map[string]interface{}{
+                "code":    -32601,
+                "message": "'" + cmd.Method + "' wasn't found",
+            }})
+        }
+    }
+}
+
+func newCDPTestBot(d *devToolsStandIn) *webBotImpl {
+    return &webBotImpl{browserName: BrowserChrome, debugPort: d.port()}
+}
+
+func TestCDPSend(t *testing.T) {
+    d := newDevToolsStandIn(t)
+    bot := newCDPTestBot(d)
+    defer bot.closeCDP()
+
+    result, err := bot.CDPSend("Browser.getVersion", nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var version struct{ Product string }
+    if err := json.Unmarshal(result, &version); err != nil || !strings.HasPrefix(version.Product, "HeadlessChrome") {
+        t.Errorf("Browser.getVersion = %s, %v", result, err)
+    }
+
+    result, err = bot.CDPSend("Runtime.evaluate", map[string]interface{}{"expression": "1+1", "returnByValue": true})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if string(result) != `{"expression":"1+1","returnByValue":true}` {
+        t.Errorf("Runtime.evaluate params = %s", result)
+    }
+
+    _, err = bot.CDPSend("Unknown.method", nil)
+    var cdpErr *CDPError
+    if !errors.As(err, &cdpErr) || cdpErr.Code != -32601 || cdpErr.Method != "Unknown.method" {
+        t.Errorf("unknown method error = %v", err)
+    }
+
+    if _, err := bot.CDPSend("Page.enable", nil); err != nil {
+        t.Errorf("command without result: %v", err)
+    }
+    if n := d.handshakes.Load(); n != 1 {
+        t.Errorf("connected %d times, want 1", n)
+    }
+}
+
+func TestSubscribeCDP(t *testing.T) {
+    d := newDevToolsStandIn(t)
+    bot := newCDPTestBot(d)
+    defer bot.closeCDP()
+
+    all, cancelAll, err := bot.SubscribeCDP("")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer cancelAll()
+    loads, cancelLoads, err := bot.SubscribeCDP("Page.loadEventFired")
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := bot.CDPSend("Page.enable", nil); err != nil {
+        t.Fatal(err)
+    }
+
+    var methods []string
+    for len(methods) < 3 {
+        select {
+        case event := <-all:
+            methods = append(methods, event.Method)
+        case <-time.After(5 * time.Second):
+            t.Fatalf("received only %v", methods)
+        }
+    }
+    if strings.Join(methods, ",") != "Page.loadEventFired,Network.requestWillBeSent,Page.domContentEventFired" {
+        t.Errorf("events = %v", methods)
+    }
+
+    select {
+    case event := <-loads:
+        if event.Method != "Page.loadEventFired" || string(event.Params) != `{"timestamp":1}` {
+            t.Errorf("filtered event = %s %s", event.Method, event.Params)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("no filtered event")
+    }
+    select {
+    case event := <-loads:
+        t.Errorf("filtered subscription received %s", event.Method)
+    case <-time.After(50 * time.Millisecond):
+    }
+
+    // 取消订阅后通道关闭
+    cancelLoads()
+    select {
+    case _, ok := <-loads:
+        if ok {
+            t.Error("filtered channel still open after cancel")
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("filtered channel not closed after cancel")
+    }
+}
+
+func TestCDPConnectionLost(t *testing.T) {
+    d := newDevToolsStandIn(t)
+    bot := newCDPTestBot(d)
+    defer bot.closeCDP()
+
+    events, _, err := bot.SubscribeCDP("")
+    if err != nil {
+        t.Fatal(err)
+    }
+    // 服务器断开连接后，等待中的命令返回ErrCDPClosed，订阅通道被关闭
+    if _, err := bot.CDPSend("Page.crash", nil); !errors.Is(err, ErrCDPClosed) {
+        t.Errorf("command on dropped connection = %v, want ErrCDPClosed", err)
+    }
+    if _, ok := <-events; ok {
+        t.Error("event channel still open after connection loss")
+    }
+    // 下一次调用重新连接
+    if _, err := bot.CDPSend("Browser.getVersion", nil); err != nil {
+        t.Errorf("reconnect: %v", err)
+    }
+    if n := d.handshakes.Load(); n != 2 {
+        t.Errorf("connected %d times, want 2", n)
+    }
+}
+
+func TestCDPConnDoesNotHoldBotLock(t *testing.T) {
+    d := newDevToolsStandIn(t)
+    d.listing = make(chan struct{})
+    d.release = make(chan struct{})
+    bot := newCDPTestBot(d)
+    defer bot.closeCDP()
+
+    const callers = 4
+    errs := make(chan error, callers)
+    for i := 0; i < callers; i++ {
+        go func() {
+            _, err := bot.CDPSend("Browser.getVersion", nil)
+            errs <- err
+        }()
+    }
+    // 所有调用都在查找调试目标，此时b.mu应该是空闲的
+    for i := 0; i < callers; i++ {
+        select {
+        case <-d.listing:
+        case <-time.After(5 * time.Second):
+            t.Fatalf("only %d callers reached /json/list", i)
+        }
+    }
+    if !bot.mu.TryLock() {
+        t.Error("b.mu is held while listing devtools targets")
+    } else {
+        bot.mu.Unlock()
+    }
+    close(d.release)
+    for i := 0; i < callers; i++ {
+        if err := <-errs; err != nil {
+            t.Errorf("concurrent CDPSend: %v", err)
+        }
+    }
+
+    // 竞争失败的连接被关闭，之后的调用复用同一个连接
+    bot.mu.Lock()
+    client := bot.cdp
+    bot.mu.Unlock()
+    for i := 0; i < 3; i++ {
+        if _, err := bot.CDPSend("Browser.getVersion", nil); err != nil {
+            t.Fatal(err)
+        }
+    }
+    bot.mu.Lock()
+    if bot.cdp != client {
+        t.Error("connection replaced after the race was settled")
+    }
+    bot.mu.Unlock()
+}
+
+func TestCDPUnsupported(t *testing.T) {
+    bot := &webBotImpl{browserName: BrowserFirefox, debugPort: 9222}
+    if _, err := bot.CDPSend("Browser.getVersion", nil); err == nil {
+        t.Error("CDPSend should fail for firefox")
+    }
+    bot = &webBotImpl{browserName: BrowserChrome}
+    if _, err := bot.CDPSend("Browser.getVersion", nil); err == nil {
+        t.Error("CDPSend should fail without a debug port")
+    }
+}
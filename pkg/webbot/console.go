@@ -0,0 +1,195 @@
+package webbot
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+)
+
+// defaultConsoleBufferSize 是控制台消息和页面错误缓冲区的默认容量
+const defaultConsoleBufferSize = 1000
+
+// ConsoleLevel 表示控制台消息的级别
+type ConsoleLevel string
+
+const (
+    ConsoleDebug   ConsoleLevel = "debug"
+    ConsoleLog     ConsoleLevel = "log"
+    ConsoleInfo    ConsoleLevel = "info"
+    ConsoleWarning ConsoleLevel = "warning"
+    ConsoleError   ConsoleLevel = "error"
+)
+
+// ConsoleMessage 表示页面输出的一条控制台消息
+// Source: 输出消息的脚本URL，Line和Column从1开始，无法确定时为0
+type ConsoleMessage struct {
+    Level  ConsoleLevel
+    Text   string
+    Source string
+    Line   int
+    Column int
+    Time   time.Time
+}
+
+// String 返回类似浏览器开发者工具的单行格式
+func (m ConsoleMessage) String() string {
+    if m.Source == "" {
+        return fmt.Sprintf("[%s] %s", m.Level, m.Text)
+    }
+    return fmt.Sprintf("[%s] %s (%s:%d:%d)", m.Level, m.Text, m.Source, m.Line, m.Column)
+}
+
+// PageError 表示页面中未被捕获的JavaScript异常
+// Stack: 异常的调用栈文本，浏览器未提供时为空
+type PageError struct {
+    Message string
+    Stack   string
+    Source  string
+    Line    int
+    Column  int
+    Time    time.Time
+}
+
+func (e *PageError) Error() string {
+    msg := "webbot: uncaught page error: " + e.Message
+    if e.Source != "" {
+        msg += fmt.Sprintf(" (%s:%d:%d)", e.Source, e.Line, e.Column)
+    }
+    return msg
+}
+
+// consoleBuffer 保存最近的控制台消息和页面错误
+// 超出容量时丢弃最旧的记录
+type consoleBuffer struct {
+    size     int
+    messages []ConsoleMessage
+    errors   []PageError
+}
+
+// appendBounded 追加一条记录，超出容量时丢弃最旧的记录
+func appendBounded[T any](items []T, item T, size int) []T {
+    items = append(items, item)
+    if size > 0 && len(items) > size {
+        items = append(items[:0], items[len(items)-size:]...)
+    }
+    return items
+}
+
+// WithConsoleBufferSize 设置控制台消息和页面错误缓冲区的容量
+// size: 各自最多保留的记录数，超出时丢弃最旧的记录，0表示不限制
+// 默认保留1000条
+// 返回WebBotOption类型的函数
+func WithConsoleBufferSize(size int) WebBotOption {
+    return func(b *webBotImpl) {
+        b.console.size = size
+    }
+}
+
+// WithFailOnPageError 设置脚本执行期间出现未捕获的JavaScript异常时是否让脚本失败
+// fail: 为true时，异常发生后脚本中后续的页面操作(Goto、ClickElement等)立即返回该*PageError，
+// ExecuteScript也会返回执行期间的第一个*PageError
+// 脚本本身返回的错误会一并返回，可以通过errors.As取得PageError
+// 返回WebBotOption类型的函数
+func WithFailOnPageError(fail bool) WebBotOption {
+    return func(b *webBotImpl) {
+        b.failOnPageError = fail
+    }
+}
+
+// 实现WebBot接口的SubscribeConsole方法
+func (b *webBotImpl) SubscribeConsole() (<-chan ConsoleMessage, func()) {
+    return b.consoleEvents.subscribe()
+}
+
+// 实现WebBot接口的SubscribePageErrors方法
+func (b *webBotImpl) SubscribePageErrors() (<-chan PageError, func()) {
+    return b.pageErrorEvents.subscribe()
+}
+
+// 实现WebBot接口的DrainConsole方法
+func (b *webBotImpl) DrainConsole() []ConsoleMessage {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    messages := b.console.messages
+    b.console.messages = nil
+    return messages
+}
+
+// 实现WebBot接口的DrainPageErrors方法
+func (b *webBotImpl) DrainPageErrors() []PageError {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    pageErrors := b.console.errors
+    b.console.errors = nil
+    return pageErrors
+}
+
+// onConsoleMessage 在驱动程序上报控制台消息时调用
+func (b *webBotImpl) onConsoleMessage(message ConsoleMessage) {
+    if message.Time.IsZero() {
+        message.Time = time.Now()
+    }
+    message.Level = ConsoleLevel(strings.ToLower(string(message.Level)))
+    if message.Level == "warn" {
+        message.Level = ConsoleWarning
+    }
+    b.mu.Lock()
+    b.console.messages = appendBounded(b.console.messages, message, b.console.size)
+    b.mu.Unlock()
+    b.consoleEvents.publish(message)
+}
+
+// onPageError 在驱动程序上报未捕获的JavaScript异常时调用
+func (b *webBotImpl) onPageError(pageErr PageError) {
+    if pageErr.Time.IsZero() {
+        pageErr.Time = time.Now()
+    }
+    b.mu.Lock()
+    b.console.errors = appendBounded(b.console.errors, pageErr, b.console.size)
+    if b.scriptRunning && b.scriptPageError == nil {
+        b.scriptPageError = &pageErr
+    }
+    b.mu.Unlock()
+    b.pageErrorEvents.publish(pageErr)
+}
+
+// beginScript 标记脚本开始执行，用于记录执行期间的页面错误
+func (b *webBotImpl) beginScript() {
+    b.mu.Lock()
+    b.scriptRunning = true
+    b.scriptPageError = nil
+    b.mu.Unlock()
+}
+
+// checkPageError 返回脚本执行期间记录的第一个页面错误
+// 只在设置了WithFailOnPageError时生效，页面操作在访问浏览器前调用，使脚本在异常发生后尽快失败
+func (b *webBotImpl) checkPageError() error {
+    if !b.failOnPageError {
+        return nil
+    }
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    if b.scriptPageError == nil {
+        return nil
+    }
+    return b.scriptPageError
+}
+
+// endScript 标记脚本执行结束
+// 如果设置了WithFailOnPageError且执行期间出现页面错误，则将其与脚本错误合并返回
+func (b *webBotImpl) endScript(scriptErr error) error {
+    b.mu.Lock()
+    pageErr := b.scriptPageError
+    b.scriptRunning = false
+    b.scriptPageError = nil
+    b.mu.Unlock()
+    if !b.failOnPageError || pageErr == nil {
+        return scriptErr
+    }
+    // 脚本返回的可能就是checkPageError返回的同一个错误
+    if errors.Is(scriptErr, pageErr) {
+        return scriptErr
+    }
+    return errors.Join(scriptErr, pageErr)
+}
@@ -0,0 +1,142 @@
+package webbot
+
+import "errors"
+
+// ErrNoDialog 表示当前页面没有打开的JavaScript弹窗
+var ErrNoDialog = errors.New("webbot: no dialog is open")
+
+// DialogType 表示JavaScript弹窗的类型
+type DialogType string
+
+const (
+    DialogAlert        DialogType = "alert"
+    DialogConfirm      DialogType = "confirm"
+    DialogPrompt       DialogType = "prompt"
+    DialogBeforeUnload DialogType = "beforeunload"
+)
+
+// Dialog 表示页面弹出的一个JavaScript弹窗
+// Type: 弹窗类型
+// Message: 弹窗显示的文本
+// DefaultValue: prompt弹窗的默认输入值，其他类型为空
+type Dialog struct {
+    Type         DialogType
+    Message      string
+    DefaultValue string
+}
+
+// DialogAction 表示对弹窗的处理方式
+// Accept: true表示接受(点击确定)，false表示取消
+// PromptText: 接受prompt弹窗时输入的文本，nil时使用DefaultValue，指向空字符串时输入空文本
+type DialogAction struct {
+    Accept     bool
+    PromptText *string
+}
+
+// DialogHandler 定义弹窗处理函数
+// 当页面弹出弹窗时调用，返回值决定如何关闭弹窗
+// 处理函数在驱动事件的goroutine中执行，不应调用会等待弹窗关闭的WebBot方法
+type DialogHandler func(dialog Dialog) DialogAction
+
+// AcceptDialogs 是一个接受所有弹窗的处理函数
+// prompt弹窗使用默认值作为输入
+func AcceptDialogs(dialog Dialog) DialogAction {
+    return DialogAction{Accept: true}
+}
+
+// DismissDialogs 是一个取消所有弹窗的处理函数
+// alert弹窗只有一个按钮，取消与接受效果相同
+func DismissDialogs(dialog Dialog) DialogAction {
+    return DialogAction{Accept: false}
+}
+
+// WithDialogHandler 设置弹窗处理策略
+// handler: 弹窗处理函数，可以是AcceptDialogs、DismissDialogs或自定义函数
+// 设置后，无人值守的脚本不会因为意外的弹窗而阻塞
+// 返回WebBotOption类型的函数
+func WithDialogHandler(handler DialogHandler) WebBotOption {
+    return func(b *webBotImpl) {
+        b.dialogHandler = handler
+    }
+}
+
+// 实现WebBot接口的GetDialog方法
+func (b *webBotImpl) GetDialog() (Dialog, error) {
+    if err := b.checkPageError(); err != nil {
+        return Dialog{}, err
+    }
+    // 实际实现将在后续添加
+    // 这里返回ErrNoDialog作为占位符
+    return Dialog{}, ErrNoDialog
+}
+
+// 实现WebBot接口的IsDialogOpen方法
+func (b *webBotImpl) IsDialogOpen() (bool, error) {
+    _, err := b.GetDialog()
+    if errors.Is(err, ErrNoDialog) {
+        return false, nil
+    }
+    if err != nil {
+        return false, err
+    }
+    return true, nil
+}
+
+// 实现WebBot接口的AcceptDialog方法
+func (b *webBotImpl) AcceptDialog() error {
+    if _, err := b.GetDialog(); err != nil {
+        return err
+    }
+    return b.closeDialog(DialogAction{Accept: true})
+}
+
+// 实现WebBot接口的DismissDialog方法
+func (b *webBotImpl) DismissDialog() error {
+    if _, err := b.GetDialog(); err != nil {
+        return err
+    }
+    return b.closeDialog(DialogAction{Accept: false})
+}
+
+// 实现WebBot接口的AcceptPrompt方法
+func (b *webBotImpl) AcceptPrompt(text string) error {
+    dialog, err := b.GetDialog()
+    if err != nil {
+        return err
+    }
+    if dialog.Type != DialogPrompt {
+        return errors.New("webbot: open dialog is not a prompt")
+    }
+    return b.closeDialog(DialogAction{Accept: true, PromptText: &text})
+}
+
+// 实现WebBot接口的SetDialogHandler方法
+func (b *webBotImpl) SetDialogHandler(handler DialogHandler) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.dialogHandler = handler
+}
+
+// closeDialog 按照指定方式关闭当前弹窗
+func (b *webBotImpl) closeDialog(action DialogAction) error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// onDialog 在驱动程序报告页面弹出弹窗时调用
+// 如果设置了弹窗处理函数，则按处理函数的结果关闭弹窗
+// 否则保持弹窗打开，由脚本通过GetDialog等方法自行处理
+func (b *webBotImpl) onDialog(dialog Dialog) error {
+    b.mu.Lock()
+    handler := b.dialogHandler
+    b.mu.Unlock()
+    if handler == nil {
+        return nil
+    }
+    action := handler(dialog)
+    if action.Accept && dialog.Type == DialogPrompt && action.PromptText == nil {
+        action.PromptText = &dialog.DefaultValue
+    }
+    return b.closeDialog(action)
+}
@@ -0,0 +1,231 @@
+package webbot
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+
+// downloadPollInterval 是等待下载完成时扫描下载目录的间隔
+const downloadPollInterval = 200 * time.Millisecond
+
+// partialDownloadSuffixes 是浏览器写入过程中使用的临时文件后缀
+// Chrome和Edge使用.crdownload，Firefox使用.part，Safari使用.download
+var partialDownloadSuffixes = []string{".crdownload", ".part", ".download"}
+
+// Download 表示一个已完成的下载文件
+// Path: 文件在脚本所在主机上的绝对路径
+// Filename: 文件名
+// Size: 文件大小(字节)
+type Download struct {
+    Path     string
+    Filename string
+    Size     int64
+}
+
+// DownloadTimeoutError 表示在指定时间内没有完成下载
+// 可以通过errors.As判断并读取等待的目录和超时时间
+type DownloadTimeoutError struct {
+    Dir     string
+    Timeout time.Duration
+}
+
+func (e *DownloadTimeoutError) Error() string {
+    return fmt.Sprintf("webbot: no download finished in %s within %s", e.Dir, e.Timeout)
+}
+
+// WithDownloadDir 设置下载目录
+// dir: 浏览器保存下载文件的目录，不存在时会自动创建
+// 浏览器启动时会将该目录写入WithUserDataDir指定的用户数据目录的首选项中
+// 并关闭下载确认对话框
+// 返回WebBotOption类型的函数
+func WithDownloadDir(dir string) WebBotOption {
+    return func(b *webBotImpl) {
+        b.downloadDir = dir
+    }
+}
+
+// isPartialDownload 判断文件名是否是浏览器写入中的临时文件
+func isPartialDownload(name string) bool {
+    for _, suffix := range partialDownloadSuffixes {
+        if strings.HasSuffix(name, suffix) {
+            return true
+        }
+    }
+    return false
+}
+
+// hasPartialPartner 判断目录中是否有与文件同名的临时文件，有则说明文件仍在写入
+func hasPartialPartner(files map[string]int64, name string) bool {
+    for _, suffix := range partialDownloadSuffixes {
+        if _, ok := files[name+suffix]; ok {
+            return true
+        }
+    }
+    return false
+}
+
+// listDownloadDir 返回下载目录中的文件及其大小
+func listDownloadDir(dir string) (map[string]int64, error) {
+    entries, err := os.ReadDir(dir)
+    if err != nil {
+        return nil, err
+    }
+    files := make(map[string]int64, len(entries))
+    for _, entry := range entries {
+        if entry.IsDir() {
+            continue
+        }
+        info, err := entry.Info()
+        if err != nil {
+            // 文件可能在扫描过程中被浏览器重命名
+            continue
+        }
+        files[entry.Name()] = info.Size()
+    }
+    return files, nil
+}
+
+// writeDownloadPreferences 将下载目录写入浏览器的用户数据目录
+// Chrome和Edge读取Default/Preferences，Firefox读取user.js
+func writeDownloadPreferences(browser BrowserName, userDataDir string, downloadDir string) error {
+    switch browser {
+    case BrowserChrome, BrowserEdge:
+        return writeChromiumPrefs(userDataDir, map[string]interface{}{
+            "download": map[string]interface{}{
+                "default_directory":   downloadDir,
+                "prompt_for_download": false,
+                "directory_upgrade":   true,
+            },
+        })
+    case BrowserFirefox:
+        return writeFirefoxPrefs(userDataDir, map[string]interface{}{
+            "browser.download.folderList":                          2,
+            "browser.download.dir":                                 downloadDir,
+            "browser.download.useDownloadDir":                      true,
+            "browser.download.always_ask_before_handling_new_types": false,
+            "browser.helperApps.neverAsk.saveToDisk":               "application/octet-stream,application/pdf,text/csv,application/vnd.ms-excel,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/zip",
+        })
+    default:
+        return fmt.Errorf("download directory is not supported for browser %s", browser)
+    }
+}
+
+// prepareDownloadDir 创建下载目录并写入浏览器首选项
+// 在浏览器启动前调用，writePrefs为false时只创建目录，用于接管已打开的浏览器
+func (b *webBotImpl) prepareDownloadDir(writePrefs bool) error {
+    if b.downloadDir == "" {
+        return nil
+    }
+    dir, err := filepath.Abs(b.downloadDir)
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(dir, 0755); err != nil {
+        return fmt.Errorf("failed to create download dir: %w", err)
+    }
+    b.downloadDir = dir
+    if !writePrefs {
+        return nil
+    }
+    return writeDownloadPreferences(b.browserName, b.userDataDir, dir)
+}
+
+// 实现WebBot接口的UploadFile方法
+func (b *webBotImpl) UploadFile(by By, paths ...string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if err := by.validate(); err != nil {
+        return err
+    }
+    if len(paths) == 0 {
+        return errors.New("webbot: no file to upload")
+    }
+    absPaths := make([]string, 0, len(paths))
+    for _, path := range paths {
+        abs, err := filepath.Abs(path)
+        if err != nil {
+            return err
+        }
+        info, err := os.Stat(abs)
+        if err != nil {
+            return fmt.Errorf("failed to upload file: %w", err)
+        }
+        if info.IsDir() {
+            return fmt.Errorf("failed to upload file: %s is a directory", abs)
+        }
+        absPaths = append(absPaths, abs)
+    }
+    return b.setInputFiles(by, absPaths)
+}
+
+// setInputFiles 将文件输入框的文件列表设置为paths
+// paths必须是绝对路径，浏览器进程的工作目录与当前进程不同
+func (b *webBotImpl) setInputFiles(by By, paths []string) error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的DownloadDir方法
+func (b *webBotImpl) DownloadDir() string {
+    return b.downloadDir
+}
+
+// 实现WebBot接口的WaitForDownload方法
+func (b *webBotImpl) WaitForDownload(trigger func() error, timeout time.Duration) (Download, error) {
+    if err := b.checkPageError(); err != nil {
+        return Download{}, err
+    }
+    dir := b.downloadDir
+    if dir == "" {
+        return Download{}, errors.New("webbot: download dir is not set, use WithDownloadDir")
+    }
+    before, err := listDownloadDir(dir)
+    if err != nil {
+        return Download{}, fmt.Errorf("failed to list download dir: %w", err)
+    }
+    if trigger != nil {
+        if err := trigger(); err != nil {
+            return Download{}, err
+        }
+    }
+
+    deadline := time.Now().Add(timeout)
+    // lastSizes记录上一次扫描时新文件的大小，大小稳定后才认为写入完成
+    lastSizes := map[string]int64{}
+    for {
+        files, err := listDownloadDir(dir)
+        if err != nil {
+            return Download{}, fmt.Errorf("failed to list download dir: %w", err)
+        }
+        expired := time.Now().After(deadline)
+        for name, size := range files {
+            if _, existed := before[name]; existed || isPartialDownload(name) {
+                continue
+            }
+            // Firefox会先创建空的目标文件，同时写入.part临时文件
+            if hasPartialPartner(files, name) {
+                continue
+            }
+            // 超时前最后一次扫描时，没有临时文件的新文件也认为已经完成，
+            // 避免超时短于扫描间隔时无法比较两次扫描的大小
+            if last, seen := lastSizes[name]; expired || seen && last == size {
+                return Download{
+                    Path:     filepath.Join(dir, name),
+                    Filename: name,
+                    Size:     size,
+                }, nil
+            }
+            lastSizes[name] = size
+        }
+        if expired {
+            return Download{}, &DownloadTimeoutError{Dir: dir, Timeout: timeout}
+        }
+        time.Sleep(downloadPollInterval)
+    }
+}
@@ -0,0 +1,129 @@
+package webbot
+
+// 实现WebBot接口的ClickElement方法
+func (b *webBotImpl) ClickElement(by By) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if err := by.validate(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的InputText方法
+func (b *webBotImpl) InputText(by By, text string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if err := by.validate(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的ClearElement方法
+func (b *webBotImpl) ClearElement(by By) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if err := by.validate(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的GetElementText方法
+func (b *webBotImpl) GetElementText(by By) (string, error) {
+    if err := b.checkPageError(); err != nil {
+        return "", err
+    }
+    if err := by.validate(); err != nil {
+        return "", err
+    }
+    // 实际实现将在后续添加
+    // 这里返回空字符串和nil作为占位符
+    return "", nil
+}
+
+// 实现WebBot接口的GetElementAttribute方法
+func (b *webBotImpl) GetElementAttribute(by By, name string) (string, error) {
+    if err := b.checkPageError(); err != nil {
+        return "", err
+    }
+    if err := by.validate(); err != nil {
+        return "", err
+    }
+    // 实际实现将在后续添加
+    // 这里返回空字符串和nil作为占位符
+    return "", nil
+}
+
+// hasElementAttribute 判断元素是否带有name属性
+// 与GetElementAttribute不同，可以区分属性不存在和属性值为空(如disabled="")
+func (b *webBotImpl) hasElementAttribute(by By, name string) (bool, error) {
+    if err := by.validate(); err != nil {
+        return false, err
+    }
+    // 实际实现将在后续添加
+    // 这里返回false和nil作为占位符
+    return false, nil
+}
+
+// 实现WebBot接口的GetElementValue方法
+func (b *webBotImpl) GetElementValue(by By) (string, error) {
+    if err := b.checkPageError(); err != nil {
+        return "", err
+    }
+    if err := by.validate(); err != nil {
+        return "", err
+    }
+    // 实际实现将在后续添加
+    // 这里返回空字符串和nil作为占位符
+    return "", nil
+}
+
+// 实现WebBot接口的SetElementValue方法
+func (b *webBotImpl) SetElementValue(by By, value string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if err := by.validate(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的SelectOption方法
+func (b *webBotImpl) SelectOption(by By, option string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if err := by.validate(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的IsElementSelected方法
+func (b *webBotImpl) IsElementSelected(by By) (bool, error) {
+    if err := b.checkPageError(); err != nil {
+        return false, err
+    }
+    if err := by.validate(); err != nil {
+        return false, err
+    }
+    // 实际实现将在后续添加
+    // 这里返回false和nil作为占位符
+    return false, nil
+}
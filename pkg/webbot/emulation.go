@@ -0,0 +1,359 @@
+package webbot
+
+import (
+    "fmt"
+    "net"
+    "net/url"
+    "runtime"
+    "strconv"
+    "strings"
+)
+
+// Viewport 表示页面视口大小
+// Width, Height: 视口的CSS像素宽高
+// DeviceScaleFactor: 设备像素比，0表示使用浏览器默认值
+// Mobile: 是否按移动设备渲染(meta viewport、滚动条等)，目前所有浏览器都不支持，设置后StartServer返回错误
+// HasTouch: 是否支持触摸事件
+type Viewport struct {
+    Width             int
+    Height            int
+    DeviceScaleFactor float64
+    Mobile            bool
+    HasTouch          bool
+}
+
+// Proxy 表示浏览器代理设置
+// Server: 代理地址，如"http://127.0.0.1:8080"、"socks5://127.0.0.1:1080"
+// Username, Password: 代理认证信息，为空表示不需要认证，目前所有浏览器都不支持，设置后StartServer返回错误
+// Bypass: 不经过代理的主机列表，如"localhost"、"*.example.com"
+type Proxy struct {
+    Server   string
+    Username string
+    Password string
+    Bypass   []string
+}
+
+// Geolocation 表示模拟的地理位置
+// Latitude, Longitude: 纬度和经度
+// Accuracy: 精度(米)，0表示使用100米
+type Geolocation struct {
+    Latitude  float64
+    Longitude float64
+    Accuracy  float64
+}
+
+// Device 表示一个移动设备模拟预设
+// 预设只模拟用户代理、视口大小、设备像素比和触摸，不设置Viewport.Mobile
+type Device struct {
+    Name      string
+    UserAgent string
+    Viewport  Viewport
+}
+
+// 常用的移动设备模拟预设
+var (
+    DeviceIPhone13 = Device{
+        Name:      "iPhone 13",
+        UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1",
+        Viewport:  Viewport{Width: 390, Height: 844, DeviceScaleFactor: 3, HasTouch: true},
+    }
+    DevicePixel7 = Device{
+        Name:      "Pixel 7",
+        UserAgent: "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Mobile Safari/537.36",
+        Viewport:  Viewport{Width: 412, Height: 915, DeviceScaleFactor: 2.625, HasTouch: true},
+    }
+    DeviceGalaxyS20 = Device{
+        Name:      "Galaxy S20",
+        UserAgent: "Mozilla/5.0 (Linux; Android 10; SM-G981B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Mobile Safari/537.36",
+        Viewport:  Viewport{Width: 360, Height: 800, DeviceScaleFactor: 3, HasTouch: true},
+    }
+    DeviceIPadAir = Device{
+        Name:      "iPad Air",
+        UserAgent: "Mozilla/5.0 (iPad; CPU OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1",
+        Viewport:  Viewport{Width: 820, Height: 1180, DeviceScaleFactor: 2, HasTouch: true},
+    }
+)
+
+// emulation 保存所有浏览器模拟相关的配置
+type emulation struct {
+    headless    bool
+    proxy       *Proxy
+    userAgent   string
+    viewport    *Viewport
+    locale      string
+    timezone    string
+    geolocation *Geolocation
+}
+
+// WithHeadless 设置是否以无头模式启动浏览器
+// headless: true表示不显示浏览器窗口
+// 返回WebBotOption类型的函数
+func WithHeadless(headless bool) WebBotOption {
+    return func(b *webBotImpl) {
+        b.emulation.headless = headless
+    }
+}
+
+// WithProxy 设置浏览器代理
+// proxy: 代理设置，支持HTTP和SOCKS代理，暂不支持用户名密码认证
+// 返回WebBotOption类型的函数
+func WithProxy(proxy Proxy) WebBotOption {
+    return func(b *webBotImpl) {
+        b.emulation.proxy = &proxy
+    }
+}
+
+// WithUserAgent 设置浏览器的User-Agent
+// userAgent: User-Agent字符串
+// 返回WebBotOption类型的函数
+func WithUserAgent(userAgent string) WebBotOption {
+    return func(b *webBotImpl) {
+        b.emulation.userAgent = userAgent
+    }
+}
+
+// WithViewport 设置页面视口大小和设备像素比
+// viewport: 视口设置
+// 启动时通过--window-size(Chrome、Edge)或-width/-height(Firefox)设置的是窗口大小，
+// 有界面模式下实际视口会比Width、Height小出标签栏和地址栏的高度
+// 返回WebBotOption类型的函数
+func WithViewport(viewport Viewport) WebBotOption {
+    return func(b *webBotImpl) {
+        b.emulation.viewport = &viewport
+    }
+}
+
+// WithLocale 设置浏览器语言区域
+// locale: 语言区域，如"zh-CN"、"en-US"
+// 影响navigator.language、Accept-Language请求头和日期数字格式
+// 返回WebBotOption类型的函数
+func WithLocale(locale string) WebBotOption {
+    return func(b *webBotImpl) {
+        b.emulation.locale = locale
+    }
+}
+
+// WithTimezone 设置浏览器时区
+// timezone: IANA时区名称，如"Asia/Shanghai"、"America/New_York"
+// 通过浏览器进程的TZ环境变量实现，Windows下不支持
+// 返回WebBotOption类型的函数
+func WithTimezone(timezone string) WebBotOption {
+    return func(b *webBotImpl) {
+        b.emulation.timezone = timezone
+    }
+}
+
+// WithGeolocation 设置模拟的地理位置，并自动授予定位权限
+// geolocation: 地理位置
+// 返回WebBotOption类型的函数
+func WithGeolocation(geolocation Geolocation) WebBotOption {
+    return func(b *webBotImpl) {
+        b.emulation.geolocation = &geolocation
+    }
+}
+
+// WithDevice 使用移动设备预设进行模拟
+// device: 设备预设，如DeviceIPhone13、DevicePixel7
+// 相当于同时设置WithUserAgent和WithViewport
+// 返回WebBotOption类型的函数
+func WithDevice(device Device) WebBotOption {
+    return func(b *webBotImpl) {
+        viewport := device.Viewport
+        b.emulation.userAgent = device.UserAgent
+        b.emulation.viewport = &viewport
+    }
+}
+
+// launchConfig 表示启动浏览器进程时需要的命令行参数和环境变量
+type launchConfig struct {
+    args []string
+    env  []string
+}
+
+// parseProxyServer 解析代理地址，返回协议、主机和端口
+func parseProxyServer(server string) (string, string, int, error) {
+    if !strings.Contains(server, "://") {
+        server = "http://" + server
+    }
+    u, err := url.Parse(server)
+    if err != nil {
+        return "", "", 0, fmt.Errorf("invalid proxy server %q: %w", server, err)
+    }
+    scheme := strings.ToLower(u.Scheme)
+    switch scheme {
+    case "http", "https", "socks4", "socks5":
+    default:
+        return "", "", 0, fmt.Errorf("invalid proxy server %q: unsupported scheme %s", server, scheme)
+    }
+    host, portStr, err := net.SplitHostPort(u.Host)
+    if err != nil {
+        return "", "", 0, fmt.Errorf("invalid proxy server %q: %w", server, err)
+    }
+    port, err := strconv.Atoi(portStr)
+    if err != nil {
+        return "", "", 0, fmt.Errorf("invalid proxy port %q", portStr)
+    }
+    return scheme, host, port, nil
+}
+
+// checkEmulation 检查当前浏览器和系统不支持的模拟配置组合
+// 这些配置无法通过启动参数或首选项实现，静默忽略会让调用方误以为已经生效
+func (b *webBotImpl) checkEmulation() error {
+    e := b.emulation
+    if e.proxy != nil && (e.proxy.Username != "" || e.proxy.Password != "") {
+        // Firefox没有保存代理凭据的首选项，Chrome和Edge需要连接后响应认证请求，尚未实现
+        return fmt.Errorf("proxy authentication is not supported for browser %s", b.browserName)
+    }
+    if e.viewport != nil && e.viewport.Mobile {
+        // Chrome和Edge需要连接后通过调试协议设置，Firefox没有对应的首选项，都尚未实现
+        return fmt.Errorf("mobile viewport emulation is not supported for browser %s", b.browserName)
+    }
+    if e.timezone != "" && runtime.GOOS == "windows" {
+        // Windows下浏览器不读取TZ环境变量
+        return fmt.Errorf("timezone emulation is not supported on %s", runtime.GOOS)
+    }
+    return nil
+}
+
+// browserLaunchConfig 将模拟配置转换为对应浏览器的启动参数和环境变量
+// Chrome和Edge使用命令行参数，Firefox主要使用user.js首选项
+// 地理位置和移动设备触摸等无法通过启动参数设置的配置，在连接后通过applyEmulation设置
+func (b *webBotImpl) browserLaunchConfig() (launchConfig, error) {
+    e := b.emulation
+    var cfg launchConfig
+    if err := b.checkEmulation(); err != nil {
+        return cfg, err
+    }
+    if e.timezone != "" {
+        cfg.env = append(cfg.env, "TZ="+e.timezone)
+    }
+
+    switch b.browserName {
+    case BrowserChrome, BrowserEdge:
+        if e.headless {
+            cfg.args = append(cfg.args, "--headless=new")
+        }
+        if e.proxy != nil {
+            scheme, host, port, err := parseProxyServer(e.proxy.Server)
+            if err != nil {
+                return cfg, err
+            }
+            cfg.args = append(cfg.args, fmt.Sprintf("--proxy-server=%s://%s", scheme, net.JoinHostPort(host, strconv.Itoa(port))))
+            if len(e.proxy.Bypass) > 0 {
+                cfg.args = append(cfg.args, "--proxy-bypass-list="+strings.Join(e.proxy.Bypass, ";"))
+            }
+        }
+        if e.userAgent != "" {
+            cfg.args = append(cfg.args, "--user-agent="+e.userAgent)
+        }
+        if e.viewport != nil {
+            // --window-size设置的是窗口大小而不是视口大小
+            cfg.args = append(cfg.args, fmt.Sprintf("--window-size=%d,%d", e.viewport.Width, e.viewport.Height))
+            if e.viewport.DeviceScaleFactor > 0 {
+                cfg.args = append(cfg.args, "--force-device-scale-factor="+strconv.FormatFloat(e.viewport.DeviceScaleFactor, 'f', -1, 64))
+            }
+            if e.viewport.HasTouch {
+                cfg.args = append(cfg.args, "--touch-events=enabled")
+            }
+        }
+        if e.locale != "" {
+            cfg.args = append(cfg.args, "--lang="+e.locale, "--accept-lang="+e.locale)
+            // Linux下Chrome根据LANGUAGE环境变量选择界面语言
+            cfg.env = append(cfg.env, "LANGUAGE="+strings.ReplaceAll(e.locale, "-", "_"))
+        }
+    case BrowserFirefox:
+        if e.headless {
+            cfg.args = append(cfg.args, "-headless")
+        }
+        if e.viewport != nil {
+            cfg.args = append(cfg.args, "-width", strconv.Itoa(e.viewport.Width), "-height", strconv.Itoa(e.viewport.Height))
+        }
+    default:
+        if e != (emulation{}) {
+            return cfg, fmt.Errorf("browser emulation options are not supported for browser %s", b.browserName)
+        }
+    }
+    return cfg, nil
+}
+
+// firefoxEmulationPrefs 返回模拟配置对应的Firefox首选项
+func (b *webBotImpl) firefoxEmulationPrefs() (map[string]interface{}, error) {
+    e := b.emulation
+    prefs := map[string]interface{}{}
+    if e.proxy != nil {
+        scheme, host, port, err := parseProxyServer(e.proxy.Server)
+        if err != nil {
+            return nil, err
+        }
+        prefs["network.proxy.type"] = 1
+        if strings.HasPrefix(scheme, "socks") {
+            prefs["network.proxy.socks"] = host
+            prefs["network.proxy.socks_port"] = port
+            prefs["network.proxy.socks_version"] = 5
+            if scheme == "socks4" {
+                prefs["network.proxy.socks_version"] = 4
+            }
+            prefs["network.proxy.socks_remote_dns"] = true
+        } else {
+            prefs["network.proxy.http"] = host
+            prefs["network.proxy.http_port"] = port
+            prefs["network.proxy.ssl"] = host
+            prefs["network.proxy.ssl_port"] = port
+        }
+        if len(e.proxy.Bypass) > 0 {
+            prefs["network.proxy.no_proxies_on"] = strings.Join(e.proxy.Bypass, ",")
+        }
+    }
+    if e.userAgent != "" {
+        prefs["general.useragent.override"] = e.userAgent
+    }
+    if e.viewport != nil && e.viewport.DeviceScaleFactor > 0 {
+        prefs["layout.css.devPixelsPerPx"] = strconv.FormatFloat(e.viewport.DeviceScaleFactor, 'f', -1, 64)
+    }
+    if e.viewport != nil && e.viewport.HasTouch {
+        prefs["dom.w3c_touch_events.enabled"] = 1
+    }
+    if e.locale != "" {
+        prefs["intl.locale.requested"] = e.locale
+        prefs["intl.accept_languages"] = e.locale
+    }
+    if e.geolocation != nil {
+        accuracy := e.geolocation.Accuracy
+        if accuracy == 0 {
+            accuracy = 100
+        }
+        prefs["geo.enabled"] = true
+        prefs["geo.prompt.testing"] = true
+        prefs["geo.prompt.testing.allow"] = true
+        prefs["permissions.default.geo"] = 1
+        prefs["geo.provider.network.url"] = fmt.Sprintf(`data:application/json,{"location":{"lat":%v,"lng":%v},"accuracy":%v}`,
+            e.geolocation.Latitude, e.geolocation.Longitude, accuracy)
+    }
+    return prefs, nil
+}
+
+// prepareEmulation 在浏览器启动前写入模拟配置需要的首选项
+func (b *webBotImpl) prepareEmulation() error {
+    if err := b.checkEmulation(); err != nil {
+        return err
+    }
+    if b.browserName != BrowserFirefox || b.emulation == (emulation{}) {
+        return nil
+    }
+    prefs, err := b.firefoxEmulationPrefs()
+    if err != nil {
+        return err
+    }
+    if len(prefs) == 0 {
+        return nil
+    }
+    return writeFirefoxPrefs(b.userDataDir, prefs)
+}
+
+// applyEmulation 在连接浏览器后设置无法通过启动参数配置的模拟项
+// 包括Chrome和Edge的地理位置和移动设备视口
+func (b *webBotImpl) applyEmulation() error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
@@ -0,0 +1,416 @@
+package webbot
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// locatorTag 是结构体字段上声明元素定位器的标签名
+// 例如：Username string `aibote:"css=#username"`
+const locatorTag = "aibote"
+
+// dateLayouts 是各类日期输入框的值格式
+var dateLayouts = map[string]string{
+    "date":           "2006-01-02",
+    "datetime-local": "2006-01-02T15:04",
+    "time":           "15:04",
+    "month":          "2006-01",
+}
+
+// secondLayouts 是设置了step属性时输入框可能返回的带秒的值格式
+// 小数秒的位数不固定，".999999999"可以匹配任意位数
+var secondLayouts = map[string][]string{
+    "datetime-local": {"2006-01-02T15:04:05", "2006-01-02T15:04:05.999999999"},
+    "time":           {"15:04:05", "15:04:05.999999999"},
+}
+
+// parseDateValue 按输入框类型解析日期值
+func parseDateValue(kind, text string) (time.Time, error) {
+    layout, ok := dateLayouts[kind]
+    if !ok {
+        return time.Parse(time.RFC3339, text)
+    }
+    t, err := time.Parse(layout, text)
+    if err == nil {
+        return t, nil
+    }
+    for _, l := range secondLayouts[kind] {
+        if t, e := time.Parse(l, text); e == nil {
+            return t, nil
+        }
+    }
+    return time.Time{}, err
+}
+
+// FormFieldError 表示填写或读取单个表单字段时的错误
+type FormFieldError struct {
+    Field   string
+    Locator By
+    Err     error
+}
+
+func (e *FormFieldError) Error() string {
+    return fmt.Sprintf("field %s (%s): %v", e.Field, e.Locator, e.Err)
+}
+
+func (e *FormFieldError) Unwrap() error {
+    return e.Err
+}
+
+// FormError 汇总一次表单操作中所有失败的字段
+// 单个字段失败不会中断其他字段的填写
+type FormError struct {
+    Fields []*FormFieldError
+}
+
+func (e *FormError) Error() string {
+    msgs := make([]string, 0, len(e.Fields))
+    for _, f := range e.Fields {
+        msgs = append(msgs, f.Error())
+    }
+    return fmt.Sprintf("webbot: %d form field(s) failed: %s", len(e.Fields), strings.Join(msgs, "; "))
+}
+
+// formField 表示一个待处理的表单字段
+type formField struct {
+    name    string
+    locator By
+    value   reflect.Value
+}
+
+// parseLocatorTag 解析aibote标签或表单键中的定位器，表单和页面对象共用同一规则
+// 不带策略的文本按name属性定位，如"username"等价于"name=username"
+func parseLocatorTag(s string) (By, error) {
+    if !strings.Contains(s, "=") {
+        return Name(s), nil
+    }
+    return ParseBy(s)
+}
+
+// structFields 返回结构体中所有带aibote标签的字段
+func structFields(v reflect.Value) ([]formField, error) {
+    var fields []formField
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        sf := t.Field(i)
+        tag, ok := sf.Tag.Lookup(locatorTag)
+        if !ok || tag == "-" || !sf.IsExported() {
+            continue
+        }
+        by, err := parseLocatorTag(tag)
+        if err != nil {
+            return nil, fmt.Errorf("field %s: %w", sf.Name, err)
+        }
+        fields = append(fields, formField{name: sf.Name, locator: by, value: v.Field(i)})
+    }
+    return fields, nil
+}
+
+// elementKind 返回元素的表单控件类型
+// 返回值为"select"、"textarea"或input元素的type属性，如"text"、"checkbox"、"date"
+func (b *webBotImpl) elementKind(by By) (string, error) {
+    element, err := b.FindElement(by)
+    if err != nil {
+        return "", err
+    }
+    tag := strings.ToLower(element.TagName)
+    if tag != "input" && tag != "" {
+        return tag, nil
+    }
+    kind, err := b.GetElementAttribute(by, "type")
+    if err != nil {
+        return "", err
+    }
+    if kind == "" {
+        kind = "text"
+    }
+    return strings.ToLower(kind), nil
+}
+
+// radioLocator 返回与by同组(name相同)、值为value的单选框定位器
+// by是链式定位器时，结果限定在by的父定位器范围内，避免匹配到其他表单中同名的单选框
+func (b *webBotImpl) radioLocator(by By, value string) (By, error) {
+    name, err := b.GetElementAttribute(by, "name")
+    if err != nil {
+        return By{}, err
+    }
+    if name == "" {
+        return By{}, errors.New("radio button has no name attribute")
+    }
+    selector := "input[type=radio][name=" + cssString(name) + "]"
+    if value == "" {
+        selector += ":checked"
+    } else {
+        selector += "[value=" + cssString(value) + "]"
+    }
+    if parent, ok := by.Parent(); ok {
+        return parent.Find(CSS(selector)), nil
+    }
+    return CSS(selector), nil
+}
+
+// cssString 将s转换为带双引号的CSS字符串，规则与CSSOM的serialize a string相同
+// Go的%q使用Go的转义规则(如\n、\u00e9)，CSS并不识别
+func cssString(s string) string {
+    var sb strings.Builder
+    sb.WriteByte('"')
+    for _, r := range s {
+        switch {
+        case r == 0:
+            sb.WriteRune('\uFFFD')
+        case r < 0x20 || r == 0x7F:
+            fmt.Fprintf(&sb, "\\%x ", r)
+        case r == '"' || r == '\\':
+            sb.WriteByte('\\')
+            sb.WriteRune(r)
+        default:
+            sb.WriteRune(r)
+        }
+    }
+    sb.WriteByte('"')
+    return sb.String()
+}
+
+// formatFieldValue 将Go值转换为表单控件的文本值
+func formatFieldValue(v reflect.Value, kind string) string {
+    if t, ok := v.Interface().(time.Time); ok {
+        if kind == "week" {
+            year, week := t.ISOWeek()
+            return fmt.Sprintf("%04d-W%02d", year, week)
+        }
+        if layout, ok := dateLayouts[kind]; ok {
+            return t.Format(layout)
+        }
+        return t.Format(time.RFC3339)
+    }
+    return fmt.Sprint(v.Interface())
+}
+
+// fillField 填写单个表单字段
+func (b *webBotImpl) fillField(by By, value reflect.Value) error {
+    for value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+        if value.IsNil() {
+            // nil值表示不修改该字段
+            return nil
+        }
+        value = value.Elem()
+    }
+    kind, err := b.elementKind(by)
+    if err != nil {
+        return err
+    }
+    switch kind {
+    case "checkbox":
+        want, err := strconv.ParseBool(formatFieldValue(value, kind))
+        if err != nil {
+            return fmt.Errorf("checkbox value must be a bool: %w", err)
+        }
+        checked, err := b.IsElementSelected(by)
+        if err != nil {
+            return err
+        }
+        if checked != want {
+            return b.ClickElement(by)
+        }
+        return nil
+    case "radio":
+        choice := formatFieldValue(value, kind)
+        if choice == "" {
+            return nil
+        }
+        radio, err := b.radioLocator(by, choice)
+        if err != nil {
+            return err
+        }
+        return b.ClickElement(radio)
+    case "select":
+        return b.SelectOption(by, formatFieldValue(value, kind))
+    case "date", "datetime-local", "time", "month", "week":
+        // 日期控件的键盘输入格式与系统区域相关，直接设置value更可靠
+        return b.SetElementValue(by, formatFieldValue(value, kind))
+    case "file":
+        return b.UploadFile(by, formatFieldValue(value, kind))
+    default:
+        if err := b.ClearElement(by); err != nil {
+            return err
+        }
+        return b.InputText(by, formatFieldValue(value, kind))
+    }
+}
+
+// readField 读取单个表单字段的当前值
+func (b *webBotImpl) readField(by By) (string, string, error) {
+    kind, err := b.elementKind(by)
+    if err != nil {
+        return "", "", err
+    }
+    switch kind {
+    case "checkbox":
+        checked, err := b.IsElementSelected(by)
+        return strconv.FormatBool(checked), kind, err
+    case "radio":
+        radio, err := b.radioLocator(by, "")
+        if err != nil {
+            return "", kind, err
+        }
+        elements, err := b.FindElements(radio)
+        if err != nil || len(elements) == 0 {
+            // 没有选中的单选框
+            return "", kind, err
+        }
+        value, err := b.GetElementAttribute(radio, "value")
+        return value, kind, err
+    default:
+        value, err := b.GetElementValue(by)
+        return value, kind, err
+    }
+}
+
+// setFieldValue 将表单控件的文本值转换后写入Go字段
+func setFieldValue(field reflect.Value, text string, kind string) error {
+    if field.Kind() == reflect.Pointer {
+        if field.IsNil() {
+            field.Set(reflect.New(field.Type().Elem()))
+        }
+        field = field.Elem()
+    }
+    if field.Type() == reflect.TypeOf(time.Time{}) {
+        if text == "" {
+            field.Set(reflect.ValueOf(time.Time{}))
+            return nil
+        }
+        if kind == "week" {
+            return errors.New("week inputs can only be read into string fields")
+        }
+        t, err := parseDateValue(kind, text)
+        if err != nil {
+            return err
+        }
+        field.Set(reflect.ValueOf(t))
+        return nil
+    }
+    switch field.Kind() {
+    case reflect.String:
+        field.SetString(text)
+    case reflect.Bool:
+        v, err := strconv.ParseBool(text)
+        if err != nil {
+            return err
+        }
+        field.SetBool(v)
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+        if text == "" {
+            field.SetInt(0)
+            return nil
+        }
+        v, err := strconv.ParseInt(text, 10, field.Type().Bits())
+        if err != nil {
+            return err
+        }
+        field.SetInt(v)
+    case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        if text == "" {
+            field.SetUint(0)
+            return nil
+        }
+        v, err := strconv.ParseUint(text, 10, field.Type().Bits())
+        if err != nil {
+            return err
+        }
+        field.SetUint(v)
+    case reflect.Float32, reflect.Float64:
+        if text == "" {
+            field.SetFloat(0)
+            return nil
+        }
+        v, err := strconv.ParseFloat(text, field.Type().Bits())
+        if err != nil {
+            return err
+        }
+        field.SetFloat(v)
+    default:
+        return fmt.Errorf("unsupported field type %s", field.Type())
+    }
+    return nil
+}
+
+// 实现WebBot接口的FillForm方法
+func (b *webBotImpl) FillForm(values interface{}) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    v := reflect.ValueOf(values)
+    for v.Kind() == reflect.Pointer && !v.IsNil() {
+        v = v.Elem()
+    }
+
+    var fields []formField
+    switch v.Kind() {
+    case reflect.Struct:
+        var err error
+        if fields, err = structFields(v); err != nil {
+            return err
+        }
+    case reflect.Map:
+        if v.Type().Key().Kind() != reflect.String {
+            return errors.New("webbot: form map keys must be locator strings")
+        }
+        keys := v.MapKeys()
+        // 按键排序，保证填写顺序稳定
+        sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+        for _, key := range keys {
+            by, err := parseLocatorTag(key.String())
+            if err != nil {
+                return fmt.Errorf("field %s: %w", key.String(), err)
+            }
+            fields = append(fields, formField{name: key.String(), locator: by, value: v.MapIndex(key)})
+        }
+    default:
+        return fmt.Errorf("webbot: FillForm expects a struct or map, got %T", values)
+    }
+
+    var formErr FormError
+    for _, f := range fields {
+        if err := b.fillField(f.locator, f.value); err != nil {
+            formErr.Fields = append(formErr.Fields, &FormFieldError{Field: f.name, Locator: f.locator, Err: err})
+        }
+    }
+    if len(formErr.Fields) > 0 {
+        return &formErr
+    }
+    return nil
+}
+
+// 实现WebBot接口的ReadForm方法
+func (b *webBotImpl) ReadForm(dst interface{}) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    v := reflect.ValueOf(dst)
+    if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+        return fmt.Errorf("webbot: ReadForm expects a non-nil struct pointer, got %T", dst)
+    }
+    fields, err := structFields(v.Elem())
+    if err != nil {
+        return err
+    }
+    var formErr FormError
+    for _, f := range fields {
+        text, kind, err := b.readField(f.locator)
+        if err == nil {
+            err = setFieldValue(f.value, text, kind)
+        }
+        if err != nil {
+            formErr.Fields = append(formErr.Fields, &FormFieldError{Field: f.name, Locator: f.locator, Err: err})
+        }
+    }
+    if len(formErr.Fields) > 0 {
+        return &formErr
+    }
+    return nil
+}
@@ -0,0 +1,578 @@
+package webbot
+
+import (
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/url"
+    "os"
+    "regexp"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+    "unicode/utf8"
+)
+
+// harCreatorName 是写入HAR文件creator字段的名称
+const harCreatorName = "go-aibote"
+
+// HAROptions 表示HAR录制选项
+// MaxEntries: 最多录制的请求数，0表示不限制，只统计已完成的请求，达到上限后新请求不再录制
+// MaxBodySize: 单个请求或响应体的最大字节数，0表示不限制，超过的部分会被丢弃
+// OmitBodies: 不录制任何请求体和响应体，只保留请求头和响应头
+// Redact: 脱敏规则
+type HAROptions struct {
+    MaxEntries  int
+    MaxBodySize int
+    OmitBodies  bool
+    Redact      HARRedaction
+}
+
+// HARRedaction 表示HAR录制时的脱敏规则
+// Headers: 需要脱敏的请求头和响应头名称，不区分大小写，如Authorization、Cookie
+// QueryParams: 需要脱敏的URL查询参数名称
+// BodyPatterns: 需要在请求体和响应体中替换的正则表达式
+// Replacement: 替换文本，为空时使用"[REDACTED]"
+type HARRedaction struct {
+    Headers      []string
+    QueryParams  []string
+    BodyPatterns []string
+    Replacement  string
+}
+
+// HARNotFound 表示HAR回放时遇到未录制请求的处理方式
+type HARNotFound string
+
+const (
+    HARNotFoundAbort    HARNotFound = "abort"
+    HARNotFoundFallback HARNotFound = "fallback"
+)
+
+// HAR 表示HAR 1.2格式的文件内容
+type HAR struct {
+    Log HARLog `json:"log"`
+}
+
+// HARLog 表示HAR文件的log对象
+type HARLog struct {
+    Version string     `json:"version"`
+    Creator HARCreator `json:"creator"`
+    Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator 表示生成HAR文件的程序
+type HARCreator struct {
+    Name    string `json:"name"`
+    Version string `json:"version"`
+}
+
+// HAREntry 表示一次完整的请求和响应
+type HAREntry struct {
+    StartedDateTime time.Time   `json:"startedDateTime"`
+    Time            float64     `json:"time"`
+    Request         HARRequest  `json:"request"`
+    Response        HARResponse `json:"response"`
+    Cache           struct{}    `json:"cache"`
+    Timings         HARTimings  `json:"timings"`
+    Comment         string      `json:"comment,omitempty"`
+}
+
+// HARRequest 表示HAR中的请求
+// RedactedQuery是录制时被脱敏的查询参数名称，回放时这些参数不参与匹配
+type HARRequest struct {
+    Method      string         `json:"method"`
+    URL         string         `json:"url"`
+    HTTPVersion string         `json:"httpVersion"`
+    Cookies     []HARNameValue `json:"cookies"`
+    Headers     []HARNameValue `json:"headers"`
+    QueryString []HARNameValue `json:"queryString"`
+    PostData    *HARPostData   `json:"postData,omitempty"`
+    HeadersSize int            `json:"headersSize"`
+    BodySize    int            `json:"bodySize"`
+
+    RedactedQuery []string `json:"_redactedQuery,omitempty"`
+}
+
+// HARResponse 表示HAR中的响应
+// Status为0表示请求失败，没有收到响应
+// RedactedHeaders是录制时被脱敏的响应头名称，回放时不发送这些响应头
+type HARResponse struct {
+    Status      int            `json:"status"`
+    StatusText  string         `json:"statusText"`
+    HTTPVersion string         `json:"httpVersion"`
+    Cookies     []HARNameValue `json:"cookies"`
+    Headers     []HARNameValue `json:"headers"`
+    Content     HARContent     `json:"content"`
+    RedirectURL string         `json:"redirectURL"`
+    HeadersSize int            `json:"headersSize"`
+    BodySize    int            `json:"bodySize"`
+
+    RedactedHeaders []string `json:"_redactedHeaders,omitempty"`
+}
+
+// HARNameValue 表示HAR中的名称/值对
+type HARNameValue struct {
+    Name  string `json:"name"`
+    Value string `json:"value"`
+}
+
+// HARPostData 表示HAR中的请求体
+type HARPostData struct {
+    MimeType string `json:"mimeType"`
+    Text     string `json:"text"`
+    Comment  string `json:"comment,omitempty"`
+}
+
+// HARContent 表示HAR中的响应体
+// 二进制内容使用base64编码，此时Encoding为"base64"
+type HARContent struct {
+    Size     int    `json:"size"`
+    MimeType string `json:"mimeType"`
+    Text     string `json:"text,omitempty"`
+    Encoding string `json:"encoding,omitempty"`
+    Comment  string `json:"comment,omitempty"`
+}
+
+// HAR内容注释，说明录制的内容与实际内容不同
+const (
+    harBodyTruncated = "body truncated"
+    harBodyOmitted   = "body omitted"
+)
+
+// harReplayDroppedHeaders 是回放时不发送的响应头
+// HAR中保存的是解压后的响应体，并且可能被截断或脱敏，这些响应头与之不再一致
+var harReplayDroppedHeaders = []string{"Content-Length", "Content-Encoding", "Transfer-Encoding"}
+
+// HARTimings 表示HAR中的耗时信息，单位毫秒
+type HARTimings struct {
+    Send    float64 `json:"send"`
+    Wait    float64 `json:"wait"`
+    Receive float64 `json:"receive"`
+}
+
+// WithHAR 录制会话中的所有请求和响应，并在StopServer时写入HAR文件
+// path: HAR文件保存路径
+// options: 录制选项，包括数量上限、大小上限和脱敏规则
+// 返回WebBotOption类型的函数
+func WithHAR(path string, options HAROptions) WebBotOption {
+    return func(b *webBotImpl) {
+        b.harPath = path
+        b.harOptions = options
+    }
+}
+
+// WithHARReplay 使用HAR文件中录制的响应回放页面请求，便于离线调试
+// path: 由WithHAR生成的HAR文件路径
+// notFound: 遇到未录制的请求时中止请求还是发送到网络
+// 返回WebBotOption类型的函数
+func WithHARReplay(path string, notFound HARNotFound) WebBotOption {
+    return func(b *webBotImpl) {
+        b.harReplayPath = path
+        b.harNotFound = notFound
+    }
+}
+
+// ReadHAR 读取HAR文件
+func ReadHAR(path string) (HAR, error) {
+    var har HAR
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return har, fmt.Errorf("failed to read har: %w", err)
+    }
+    if err := json.Unmarshal(data, &har); err != nil {
+        return har, fmt.Errorf("failed to parse har %s: %w", path, err)
+    }
+    return har, nil
+}
+
+// harRecorder 根据网络事件构建HAR条目
+type harRecorder struct {
+    options  HAROptions
+    patterns []*regexp.Regexp
+
+    mu      sync.Mutex
+    pending map[string]*HAREntry
+    entries []HAREntry
+}
+
+// newHARRecorder 创建HAR录制器并编译脱敏规则
+func newHARRecorder(options HAROptions) (*harRecorder, error) {
+    r := &harRecorder{
+        options: options,
+        pending: map[string]*HAREntry{},
+    }
+    for _, pattern := range options.Redact.BodyPatterns {
+        re, err := regexp.Compile(pattern)
+        if err != nil {
+            return nil, fmt.Errorf("invalid har redaction pattern %q: %w", pattern, err)
+        }
+        r.patterns = append(r.patterns, re)
+    }
+    return r, nil
+}
+
+// full 判断已完成的条目是否达到MaxEntries，调用方需持有r.mu
+func (r *harRecorder) full() bool {
+    return r.options.MaxEntries > 0 && len(r.entries) >= r.options.MaxEntries
+}
+
+// record 处理一个网络事件
+// 由onNetworkEvent直接调用，不经过会丢弃事件的订阅通道
+func (r *harRecorder) record(event NetworkEvent) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    switch event.Type {
+    case NetworkRequest:
+        // 只有完成的请求计入MaxEntries，未完成的请求可能失败或永远不会完成
+        if r.full() {
+            return
+        }
+        entry := &HAREntry{
+            StartedDateTime: event.Time,
+            Request:         r.request(event.Request),
+        }
+        r.pending[event.Request.ID] = entry
+    case NetworkResponse, NetworkFailed:
+        entry, ok := r.pending[event.Request.ID]
+        if !ok {
+            return
+        }
+        delete(r.pending, event.Request.ID)
+        if r.full() {
+            return
+        }
+        if event.Type == NetworkResponse {
+            entry.Response = r.response(event.Response)
+        } else {
+            entry.Response = HARResponse{
+                HTTPVersion: "HTTP/1.1",
+                Cookies:     []HARNameValue{},
+                Headers:     []HARNameValue{},
+                HeadersSize: -1,
+                BodySize:    -1,
+            }
+            entry.Comment = event.ErrorText
+        }
+        elapsed := float64(event.Time.Sub(entry.StartedDateTime)) / float64(time.Millisecond)
+        if elapsed < 0 {
+            elapsed = 0
+        }
+        entry.Time = elapsed
+        entry.Timings = HARTimings{Wait: elapsed}
+        r.entries = append(r.entries, *entry)
+    }
+}
+
+// har 返回当前已完成的所有条目组成的HAR
+func (r *harRecorder) har() HAR {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    entries := make([]HAREntry, len(r.entries))
+    copy(entries, r.entries)
+    sort.SliceStable(entries, func(i, j int) bool {
+        return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+    })
+    return HAR{Log: HARLog{
+        Version: "1.2",
+        Creator: HARCreator{Name: harCreatorName, Version: "1.0"},
+        Entries: entries,
+    }}
+}
+
+// replacement 返回脱敏替换文本
+func (r *harRecorder) replacement() string {
+    if r.options.Redact.Replacement != "" {
+        return r.options.Redact.Replacement
+    }
+    return "[REDACTED]"
+}
+
+// headers 将请求头转换为HAR格式并脱敏，结果按名称排序
+// 同时返回被脱敏的请求头名称
+func (r *harRecorder) headers(headers map[string]string) ([]HARNameValue, []string) {
+    result := make([]HARNameValue, 0, len(headers))
+    var redactedNames []string
+    for name, value := range headers {
+        if containsFold(r.options.Redact.Headers, name) {
+            value = r.replacement()
+            redactedNames = append(redactedNames, name)
+        }
+        result = append(result, HARNameValue{Name: name, Value: value})
+    }
+    sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+    sort.Strings(redactedNames)
+    return result, redactedNames
+}
+
+// containsFold 不区分大小写地判断names中是否包含name
+func containsFold(names []string, name string) bool {
+    for _, n := range names {
+        if strings.EqualFold(n, name) {
+            return true
+        }
+    }
+    return false
+}
+
+// url 对URL中需要脱敏的查询参数替换取值，URL的其他部分和参数顺序保持不变
+// 返回脱敏后的URL、查询参数列表和被脱敏的参数名称
+func (r *harRecorder) url(rawURL string) (string, []HARNameValue, []string) {
+    query := []HARNameValue{}
+    u, err := url.Parse(rawURL)
+    if err != nil || u.RawQuery == "" {
+        return rawURL, query, nil
+    }
+    parts := strings.Split(u.RawQuery, "&")
+    var redactedNames []string
+    for i, part := range parts {
+        if part == "" {
+            continue
+        }
+        rawName, rawValue, _ := strings.Cut(part, "=")
+        name, err := url.QueryUnescape(rawName)
+        if err != nil {
+            name = rawName
+        }
+        value, err := url.QueryUnescape(rawValue)
+        if err != nil {
+            value = rawValue
+        }
+        for _, redacted := range r.options.Redact.QueryParams {
+            if name == redacted {
+                value = r.replacement()
+                parts[i] = rawName + "=" + url.QueryEscape(value)
+                redactedNames = append(redactedNames, name)
+                break
+            }
+        }
+        query = append(query, HARNameValue{Name: name, Value: value})
+    }
+    if len(redactedNames) == 0 {
+        return rawURL, query, nil
+    }
+    u.RawQuery = strings.Join(parts, "&")
+    return u.String(), query, redactedNames
+}
+
+// body 对请求体或响应体进行截断和脱敏
+// 返回处理后的内容，以及内容被省略或截断时的注释
+func (r *harRecorder) body(data []byte) ([]byte, string) {
+    if r.options.OmitBodies {
+        if len(data) > 0 {
+            return nil, harBodyOmitted
+        }
+        return nil, ""
+    }
+    // 先脱敏再截断，避免敏感内容被截断后无法匹配
+    for _, re := range r.patterns {
+        data = re.ReplaceAll(data, []byte(r.replacement()))
+    }
+    if r.options.MaxBodySize > 0 && len(data) > r.options.MaxBodySize {
+        return data[:r.options.MaxBodySize], harBodyTruncated
+    }
+    return data, ""
+}
+
+// request 将请求转换为HAR格式
+func (r *harRecorder) request(req Request) HARRequest {
+    u, query, redactedQuery := r.url(req.URL)
+    headers, _ := r.headers(req.Headers)
+    result := HARRequest{
+        Method:        req.Method,
+        URL:           u,
+        HTTPVersion:   "HTTP/1.1",
+        Cookies:       []HARNameValue{},
+        Headers:       headers,
+        QueryString:   query,
+        HeadersSize:   -1,
+        BodySize:      len(req.PostData),
+        RedactedQuery: redactedQuery,
+    }
+    if req.PostData != "" {
+        data, comment := r.body([]byte(req.PostData))
+        result.PostData = &HARPostData{
+            MimeType: headerValue(req.Headers, "Content-Type"),
+            Text:     string(data),
+            Comment:  comment,
+        }
+    }
+    return result
+}
+
+// response 将响应转换为HAR格式
+func (r *harRecorder) response(resp Response) HARResponse {
+    data, comment := r.body(resp.Body)
+    content := HARContent{
+        Size:     len(resp.Body),
+        MimeType: resp.MimeType,
+        Comment:  comment,
+    }
+    if utf8.Valid(data) {
+        content.Text = string(data)
+    } else {
+        content.Text = base64.StdEncoding.EncodeToString(data)
+        content.Encoding = "base64"
+    }
+    headers, redactedHeaders := r.headers(resp.Headers)
+    return HARResponse{
+        Status:          resp.Status,
+        StatusText:      resp.StatusText,
+        HTTPVersion:     "HTTP/1.1",
+        Cookies:         []HARNameValue{},
+        Headers:         headers,
+        Content:         content,
+        RedirectURL:     headerValue(resp.Headers, "Location"),
+        HeadersSize:     -1,
+        BodySize:        len(resp.Body),
+        RedactedHeaders: redactedHeaders,
+    }
+}
+
+// headerValue 不区分大小写地获取请求头或响应头的值
+func headerValue(headers map[string]string, name string) string {
+    for k, v := range headers {
+        if strings.EqualFold(k, name) {
+            return v
+        }
+    }
+    return ""
+}
+
+// writeHAR 将HAR写入文件
+func writeHAR(path string, har HAR) error {
+    data, err := json.MarshalIndent(har, "", "  ")
+    if err != nil {
+        return fmt.Errorf("failed to encode har: %w", err)
+    }
+    if err := os.WriteFile(path, data, 0644); err != nil {
+        return fmt.Errorf("failed to write har: %w", err)
+    }
+    return nil
+}
+
+// startHARRecording 开始录制网络事件
+func (b *webBotImpl) startHARRecording() error {
+    recorder, err := newHARRecorder(b.harOptions)
+    if err != nil {
+        return err
+    }
+    b.harRecorder = recorder
+    return nil
+}
+
+// stopHARRecording 将录制的请求写入HAR文件
+func (b *webBotImpl) stopHARRecording() error {
+    if b.harRecorder == nil {
+        return nil
+    }
+    return writeHAR(b.harPath, b.harRecorder.har())
+}
+
+// 实现WebBot接口的SaveHAR方法
+func (b *webBotImpl) SaveHAR(path string) error {
+    if b.harRecorder == nil {
+        return errors.New("webbot: har recording is not enabled, use WithHAR")
+    }
+    return writeHAR(path, b.harRecorder.har())
+}
+
+// harRequestKey 返回回放时匹配请求使用的键
+// 方法不区分大小写，忽略URL片段，查询参数按名称和值排序后比较
+// ignore中的查询参数在录制时被脱敏，不参与匹配
+func harRequestKey(method, rawURL string, ignore map[string]bool) string {
+    method = strings.ToUpper(method)
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        return method + " " + rawURL
+    }
+    var params []string
+    for name, values := range u.Query() {
+        if ignore[name] {
+            continue
+        }
+        for _, v := range values {
+            params = append(params, url.QueryEscape(name)+"="+url.QueryEscape(v))
+        }
+    }
+    sort.Strings(params)
+    return method + " " + strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + u.EscapedPath() + "?" + strings.Join(params, "&")
+}
+
+// startHARReplay 注册一个路由，用HAR中录制的响应回放请求
+// 同一个URL和方法被录制多次时，按录制顺序依次回放，最后一条会被重复使用
+// 录制时失败的请求在回放时同样以失败中止
+func (b *webBotImpl) startHARReplay() error {
+    har, err := ReadHAR(b.harReplayPath)
+    if err != nil {
+        return err
+    }
+    ignore := map[string]bool{}
+    for _, entry := range har.Log.Entries {
+        for _, name := range entry.Request.RedactedQuery {
+            ignore[name] = true
+        }
+    }
+    var mu sync.Mutex
+    recorded := map[string][]HAREntry{}
+    for _, entry := range har.Log.Entries {
+        key := harRequestKey(entry.Request.Method, entry.Request.URL, ignore)
+        recorded[key] = append(recorded[key], entry)
+    }
+    return b.Route("**", func(route *Route) {
+        key := harRequestKey(route.Request.Method, route.Request.URL, ignore)
+        mu.Lock()
+        entries := recorded[key]
+        var entry HAREntry
+        found := len(entries) > 0
+        if found {
+            entry = entries[0]
+            if len(entries) > 1 {
+                recorded[key] = entries[1:]
+            }
+        }
+        mu.Unlock()
+
+        if !found {
+            if b.harNotFound == HARNotFoundFallback {
+                route.Continue(ContinueOptions{})
+            } else {
+                route.Abort(AbortFailed)
+            }
+            return
+        }
+        if entry.Response.Status == 0 {
+            route.Abort(AbortFailed)
+            return
+        }
+        route.Fulfill(FulfillOptions{
+            Status:  entry.Response.Status,
+            Headers: harReplayHeaders(entry.Response),
+            Body:    harReplayBody(entry.Response.Content),
+        })
+    })
+}
+
+// harReplayHeaders 返回回放时发送的响应头
+// 去掉被脱敏的响应头，以及与录制的响应体不再一致的长度和编码响应头
+func harReplayHeaders(resp HARResponse) map[string]string {
+    headers := map[string]string{}
+    for _, h := range resp.Headers {
+        if containsFold(harReplayDroppedHeaders, h.Name) || containsFold(resp.RedactedHeaders, h.Name) {
+            continue
+        }
+        headers[h.Name] = h.Value
+    }
+    return headers
+}
+
+// harReplayBody 返回回放时发送的响应体
+func harReplayBody(content HARContent) []byte {
+    if content.Encoding == "base64" {
+        if decoded, err := base64.StdEncoding.DecodeString(content.Text); err == nil {
+            return decoded
+        }
+    }
+    return []byte(content.Text)
+}
@@ -0,0 +1,375 @@
+package webbot
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "net"
+    "os"
+    "net/url"
+    "os/exec"
+    "path/filepath"
+    "regexp"
+    "runtime"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// stderrTailSize 是保留的浏览器stderr输出的最大字节数
+const stderrTailSize = 64 * 1024
+
+// devToolsEndpointPattern 匹配浏览器启动后在stderr中输出的调试地址
+// Chrome和Edge输出"DevTools listening on ws://..."，Firefox输出"WebDriver BiDi listening on ws://..."
+var devToolsEndpointPattern = regexp.MustCompile(`(?:DevTools|WebDriver BiDi) listening on (ws://\S+)`)
+
+// ErrBrowserNotFound 表示找不到浏览器可执行文件
+var ErrBrowserNotFound = errors.New("webbot: browser executable not found")
+
+// BrowserLaunchError 表示浏览器启动失败或调试端口未在超时时间内就绪
+// Stderr保存浏览器最后输出的错误信息，便于诊断
+type BrowserLaunchError struct {
+    Path   string
+    Port   int
+    Err    error
+    Stderr string
+}
+
+func (e *BrowserLaunchError) Error() string {
+    msg := fmt.Sprintf("webbot: failed to launch %s on debug port %d: %v", e.Path, e.Port, e.Err)
+    if e.Stderr != "" {
+        msg += "\nbrowser stderr:\n" + e.Stderr
+    }
+    return msg
+}
+
+func (e *BrowserLaunchError) Unwrap() error {
+    return e.Err
+}
+
+// WithLaunchTimeout 设置等待浏览器调试端口就绪的最长时间
+// timeout: 超时时间，默认30秒
+// 返回WebBotOption类型的函数
+func WithLaunchTimeout(timeout time.Duration) WebBotOption {
+    return func(b *webBotImpl) {
+        b.launchTimeout = timeout
+    }
+}
+
+// browserCandidates 返回各平台上浏览器的常见安装路径和命令名
+func browserCandidates(name BrowserName) []string {
+    switch runtime.GOOS {
+    case "windows":
+        programFiles := []string{os.Getenv("ProgramFiles"), os.Getenv("ProgramFiles(x86)"), os.Getenv("LOCALAPPDATA")}
+        var rel []string
+        switch name {
+        case BrowserChrome:
+            rel = []string{`Google\Chrome\Application\chrome.exe`}
+        case BrowserEdge:
+            rel = []string{`Microsoft\Edge\Application\msedge.exe`}
+        case BrowserFirefox:
+            rel = []string{`Mozilla Firefox\firefox.exe`}
+        }
+        var paths []string
+        for _, dir := range programFiles {
+            if dir == "" {
+                continue
+            }
+            for _, r := range rel {
+                paths = append(paths, filepath.Join(dir, r))
+            }
+        }
+        return paths
+    case "darwin":
+        switch name {
+        case BrowserChrome:
+            return []string{"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome"}
+        case BrowserEdge:
+            return []string{"/Applications/Microsoft Edge.app/Contents/MacOS/Microsoft Edge"}
+        case BrowserFirefox:
+            return []string{"/Applications/Firefox.app/Contents/MacOS/firefox"}
+        case BrowserSafari:
+            return []string{"/Applications/Safari.app/Contents/MacOS/Safari"}
+        }
+    default:
+        switch name {
+        case BrowserChrome:
+            return []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser"}
+        case BrowserEdge:
+            return []string{"microsoft-edge", "microsoft-edge-stable"}
+        case BrowserFirefox:
+            return []string{"firefox"}
+        }
+    }
+    return nil
+}
+
+// resolveBrowserPath 返回浏览器可执行文件路径
+// 优先使用WithBrowserPath指定的路径，否则按平台查找常见安装位置
+func (b *webBotImpl) resolveBrowserPath() (string, error) {
+    if b.browserPath != "" {
+        if _, err := os.Stat(b.browserPath); err != nil {
+            return "", fmt.Errorf("%w: %v", ErrBrowserNotFound, err)
+        }
+        return b.browserPath, nil
+    }
+    for _, candidate := range browserCandidates(b.browserName) {
+        if filepath.IsAbs(candidate) {
+            if _, err := os.Stat(candidate); err == nil {
+                return candidate, nil
+            }
+            continue
+        }
+        if path, err := exec.LookPath(candidate); err == nil {
+            return path, nil
+        }
+    }
+    return "", fmt.Errorf("%w: %s, use WithBrowserPath", ErrBrowserNotFound, b.browserName)
+}
+
+// freePort 返回一个当前空闲的本地TCP端口
+func freePort() (int, error) {
+    l, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        return 0, err
+    }
+    defer l.Close()
+    return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// portReady 判断本地端口是否已经在监听
+func portReady(port int) bool {
+    conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), 200*time.Millisecond)
+    if err != nil {
+        return false
+    }
+    conn.Close()
+    return true
+}
+
+// maxEndpointLineLength 是查找调试地址时缓存的最长不完整行
+const maxEndpointLineLength = 4096
+
+// tailBuffer 只保留最后写入的limit个字节，用于收集浏览器的stderr
+// 同时记录输出中第一次出现的调试地址
+type tailBuffer struct {
+    mu       sync.Mutex
+    limit    int
+    data     []byte
+    line     []byte // 尚未遇到换行符的最后一行
+    endpoint string
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    t.data = append(t.data, p...)
+    if t.endpoint == "" {
+        // 一次写入可能停在调试地址中间，只在完整的行中查找
+        rest := p
+        for t.endpoint == "" {
+            i := bytes.IndexByte(rest, '\n')
+            if i < 0 {
+                t.line = append(t.line, rest...)
+                if len(t.line) > maxEndpointLineLength {
+                    // 调试地址所在的行不会这么长
+                    t.line = t.line[:0]
+                }
+                break
+            }
+            t.line = append(t.line, rest[:i]...)
+            if m := devToolsEndpointPattern.FindSubmatch(t.line); m != nil {
+                t.endpoint = string(m[1])
+            }
+            t.line = t.line[:0]
+            rest = rest[i+1:]
+        }
+        if t.endpoint != "" {
+            t.line = nil
+        }
+    }
+    if len(t.data) > t.limit {
+        t.data = t.data[len(t.data)-t.limit:]
+    }
+    return len(p), nil
+}
+
+// Endpoint 返回浏览器输出的调试地址，尚未输出时返回空字符串
+func (t *tailBuffer) Endpoint() string {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    return t.endpoint
+}
+
+func (t *tailBuffer) String() string {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    return string(t.data)
+}
+
+// browserProcess 表示由WebBot启动的浏览器进程
+type browserProcess struct {
+    cmd     *exec.Cmd
+    stderr  *tailBuffer
+    done    chan struct{}
+    waitErr error
+}
+
+// exited 判断浏览器进程是否已经退出
+func (p *browserProcess) exited() bool {
+    select {
+    case <-p.done:
+        return true
+    default:
+        return false
+    }
+}
+
+// prepareProfileDir 准备用户数据目录
+// 用户数据目录为空时创建临时目录，StopServer时删除
+func (b *webBotImpl) prepareProfileDir() error {
+    if b.userDataDir != "" {
+        return nil
+    }
+    dir, err := os.MkdirTemp("", "go-aibote-profile-")
+    if err != nil {
+        return fmt.Errorf("failed to create temp profile dir: %w", err)
+    }
+    b.userDataDir = dir
+    b.tempProfileDir = dir
+    return nil
+}
+
+// browserCommandArgs 返回启动浏览器的完整命令行参数
+func (b *webBotImpl) browserCommandArgs(port int, cfg launchConfig) []string {
+    var args []string
+    switch b.browserName {
+    case BrowserFirefox:
+        args = []string{
+            "--remote-debugging-port=" + strconv.Itoa(port),
+            "-profile", b.userDataDir,
+            "-no-remote",
+        }
+    default:
+        args = []string{
+            "--remote-debugging-port=" + strconv.Itoa(port),
+            "--user-data-dir=" + b.userDataDir,
+            "--no-first-run",
+            "--no-default-browser-check",
+        }
+    }
+    args = append(args, cfg.args...)
+    args = append(args, b.arguments...)
+    return append(args, "about:blank")
+}
+
+// endpointPort 返回调试地址中的端口
+func endpointPort(endpoint string) (int, error) {
+    u, err := url.Parse(endpoint)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.Atoi(u.Port())
+}
+
+// launchBrowser 启动浏览器并等待调试端口就绪
+// 浏览器在stderr中输出调试地址后，以地址中的端口为准
+// 未指定端口时，Chrome和Edge使用端口0由浏览器自行选择，避免预先分配的端口被占用
+func (b *webBotImpl) launchBrowser(cfg launchConfig) error {
+    if b.browserName == BrowserSafari {
+        return errors.New("webbot: launching safari is not supported, start it with remote automation and use WithDebugPort")
+    }
+    path, err := b.resolveBrowserPath()
+    if err != nil {
+        return err
+    }
+    port := b.debugPort
+    if port == 0 && b.browserName == BrowserFirefox {
+        if port, err = freePort(); err != nil {
+            return fmt.Errorf("failed to allocate debug port: %w", err)
+        }
+    }
+
+    cmd := exec.Command(path, b.browserCommandArgs(port, cfg)...)
+    cmd.Env = append(os.Environ(), cfg.env...)
+    stderr := &tailBuffer{limit: stderrTailSize}
+    cmd.Stderr = stderr
+    setProcessGroup(cmd)
+    if err := cmd.Start(); err != nil {
+        return &BrowserLaunchError{Path: path, Port: port, Err: err}
+    }
+    proc := &browserProcess{cmd: cmd, stderr: stderr, done: make(chan struct{})}
+    go func() {
+        proc.waitErr = cmd.Wait()
+        close(proc.done)
+    }()
+
+    timeout := b.launchTimeout
+    deadline := time.Now().Add(timeout)
+    for {
+        if endpoint := stderr.Endpoint(); endpoint != "" {
+            if p, err := endpointPort(endpoint); err == nil && portReady(p) {
+                port = p
+                break
+            }
+        } else if port != 0 && portReady(port) {
+            break
+        }
+        if proc.exited() {
+            err := proc.waitErr
+            if err == nil {
+                err = errors.New("browser exited before debug port was ready")
+            }
+            return &BrowserLaunchError{Path: path, Port: port, Err: err, Stderr: stderr.String()}
+        }
+        if time.Now().After(deadline) {
+            killProcessTree(cmd)
+            <-proc.done
+            return &BrowserLaunchError{
+                Path:   path,
+                Port:   port,
+                Err:    fmt.Errorf("debug port not ready within %s", timeout),
+                Stderr: stderr.String(),
+            }
+        }
+        time.Sleep(100 * time.Millisecond)
+    }
+    b.debugPort = port
+    b.browser = proc
+    return nil
+}
+
+// stopBrowser 结束浏览器进程树并删除临时用户数据目录
+func (b *webBotImpl) stopBrowser() error {
+    var errs []error
+    if b.browser != nil {
+        if !b.browser.exited() {
+            if err := killProcessTree(b.browser.cmd); err != nil {
+                errs = append(errs, fmt.Errorf("failed to kill browser: %w", err))
+            }
+            <-b.browser.done
+        }
+        b.browser = nil
+    }
+    if b.tempProfileDir != "" {
+        if err := os.RemoveAll(b.tempProfileDir); err != nil {
+            errs = append(errs, fmt.Errorf("failed to remove temp profile dir: %w", err))
+        }
+        b.userDataDir = ""
+        b.tempProfileDir = ""
+    }
+    return errors.Join(errs...)
+}
+
+// 实现WebBot接口的BrowserStderr方法
+func (b *webBotImpl) BrowserStderr() string {
+    if b.browser == nil {
+        return ""
+    }
+    return b.browser.stderr.String()
+}
+
+// 实现WebBot接口的DebugPort方法
+func (b *webBotImpl) DebugPort() int {
+    return b.debugPort
+}
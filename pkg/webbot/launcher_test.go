@@ -0,0 +1,325 @@
+package webbot
+
+import (
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "runtime"
+    "strconv"
+    "strings"
+    "syscall"
+    "testing"
+    "time"
+)
+
+// 测试二进制文件同时充当桩浏览器：设置了stubBrowserEnv时TestMain不运行测试，
+// 而是按环境变量指定的模式模拟浏览器的行为
+const (
+    stubBrowserEnv    = "GO_AIBOTE_STUB_BROWSER"
+    stubBrowserDirEnv = "GO_AIBOTE_STUB_BROWSER_DIR"
+)
+
+func TestMain(m *testing.M) {
+    if mode := os.Getenv(stubBrowserEnv); mode != "" {
+        runStubBrowser(mode, os.Args[1:])
+        return
+    }
+    os.Exit(m.Run())
+}
+
+// runStubBrowser 模拟浏览器进程
+// ok: 监听--remote-debugging-port指定的端口(0表示随机端口)，在stderr输出调试地址，并启动一个子进程
+// quiet: 与ok相同，但不输出调试地址，只能通过轮询端口发现
+// hang: 既不监听也不输出，用于测试超时
+// crash: 输出错误信息后以状态码3退出
+// child: 记录自己的PID后一直运行，用于测试结束进程树
+func runStubBrowser(mode string, args []string) {
+    dir := os.Getenv(stubBrowserDirEnv)
+    switch mode {
+    case "child":
+        os.WriteFile(filepath.Join(dir, "child.pid"), []byte(strconv.Itoa(os.Getpid())), 0644)
+        time.Sleep(time.Hour)
+        return
+    case "crash":
+        fmt.Fprintln(os.Stderr, "[0101/000000.000:FATAL:stub] cannot open display")
+        os.Exit(3)
+    case "hang":
+        fmt.Fprintln(os.Stderr, "stub browser starting")
+        time.Sleep(time.Hour)
+        return
+    }
+
+    os.WriteFile(filepath.Join(dir, "args"), []byte(strings.Join(args, "\n")), 0644)
+    port := "0"
+    for _, arg := range args {
+        if v, ok := strings.CutPrefix(arg, "--remote-debugging-port="); ok {
+            port = v
+        }
+    }
+    l, err := net.Listen("tcp", "127.0.0.1:"+port)
+    if err != nil {
+        fmt.Fprintln(os.Stderr, "stub browser:", err)
+        os.Exit(1)
+    }
+    child := exec.Command(os.Args[0])
+    child.Env = append(os.Environ(), stubBrowserEnv+"=child")
+    child.Start()
+
+    fmt.Fprintln(os.Stderr, "[0101/000000.000:WARNING:stub] some startup noise")
+    if mode == "ok" {
+        fmt.Fprintf(os.Stderr, "\nDevTools listening on ws://%s/devtools/browser/stub-id\n", l.Addr())
+    }
+    http.Serve(l, http.NotFoundHandler())
+}
+
+// newStubBrowserBot 创建使用桩浏览器的WebBot，并把临时用户数据目录放在测试目录中
+func newStubBrowserBot(t *testing.T, mode string, options ...WebBotOption) (*webBotImpl, string) {
+    t.Helper()
+    dir := t.TempDir()
+    t.Setenv(stubBrowserEnv, mode)
+    t.Setenv(stubBrowserDirEnv, dir)
+    tmp := t.TempDir()
+    t.Setenv("TMPDIR", tmp)
+    t.Setenv("TMP", tmp)
+    t.Setenv("TEMP", tmp)
+    options = append([]WebBotOption{
+        WithBrowserPath(os.Args[0]),
+        WithUserDataDir(""),
+        WithLaunchTimeout(10 * time.Second),
+    }, options...)
+    bot, err := NewWebBot(options...)
+    if err != nil {
+        t.Fatal(err)
+    }
+    impl := bot.(*webBotImpl)
+    t.Cleanup(func() { impl.StopServer() })
+    return impl, dir
+}
+
+// profileDirs 返回临时目录中由prepareProfileDir创建的用户数据目录
+func profileDirs(t *testing.T) []string {
+    t.Helper()
+    matches, err := filepath.Glob(filepath.Join(os.TempDir(), "go-aibote-profile-*"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    return matches
+}
+
+// processAlive 判断进程是否仍在运行
+func processAlive(pid int) bool {
+    p, err := os.FindProcess(pid)
+    if err != nil {
+        return false
+    }
+    return p.Signal(syscall.Signal(0)) == nil
+}
+
+func TestLaunchDiscoversEndpointAndStopCleansUp(t *testing.T) {
+    bot, dir := newStubBrowserBot(t, "ok")
+    if err := bot.StartServer("127.0.0.1", 0); err != nil {
+        t.Fatal(err)
+    }
+
+    // 未指定端口时以端口0启动，并从stderr中的调试地址得知实际端口
+    args, err := os.ReadFile(filepath.Join(dir, "args"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !strings.Contains(string(args), "--remote-debugging-port=0\n") {
+        t.Errorf("browser args = %q, want --remote-debugging-port=0", args)
+    }
+    if bot.DebugPort() == 0 || !portReady(bot.DebugPort()) {
+        t.Errorf("debug port %d is not ready", bot.DebugPort())
+    }
+    if !strings.Contains(bot.BrowserStderr(), "DevTools listening on") {
+        t.Errorf("stderr = %q", bot.BrowserStderr())
+    }
+    profiles := profileDirs(t)
+    if len(profiles) != 1 || !strings.Contains(string(args), "--user-data-dir="+profiles[0]) {
+        t.Fatalf("temp profiles %v, browser args %q", profiles, args)
+    }
+
+    // 重复启动不会产生孤儿进程
+    if err := bot.StartServer("127.0.0.1", 0); err == nil {
+        t.Error("second StartServer should fail while the browser is running")
+    }
+
+    var childPID int
+    deadline := time.Now().Add(5 * time.Second)
+    for childPID == 0 && time.Now().Before(deadline) {
+        data, _ := os.ReadFile(filepath.Join(dir, "child.pid"))
+        childPID, _ = strconv.Atoi(string(data))
+        time.Sleep(20 * time.Millisecond)
+    }
+    if childPID == 0 {
+        t.Fatal("stub browser child did not start")
+    }
+
+    port := bot.DebugPort()
+    proc := bot.browser
+    if err := bot.StopServer(); err != nil {
+        t.Fatal(err)
+    }
+    if !proc.exited() {
+        t.Error("browser process still running after StopServer")
+    }
+    if portReady(port) {
+        t.Error("debug port still listening after StopServer")
+    }
+    if profiles := profileDirs(t); len(profiles) != 0 {
+        t.Errorf("temp profile dirs left behind: %v", profiles)
+    }
+    if runtime.GOOS != "windows" {
+        // 子进程被一起结束，等待它被回收
+        deadline := time.Now().Add(5 * time.Second)
+        for processAlive(childPID) && time.Now().Before(deadline) {
+            time.Sleep(20 * time.Millisecond)
+        }
+        if processAlive(childPID) {
+            t.Errorf("browser child process %d survived StopServer", childPID)
+        }
+    }
+}
+
+func TestLaunchFixedPortWithoutEndpointLine(t *testing.T) {
+    port, err := freePort()
+    if err != nil {
+        t.Fatal(err)
+    }
+    bot, _ := newStubBrowserBot(t, "quiet", WithDebugPort(port))
+    if err := bot.StartServer("127.0.0.1", 0); err != nil {
+        t.Fatal(err)
+    }
+    if bot.DebugPort() != port {
+        t.Errorf("debug port = %d, want %d", bot.DebugPort(), port)
+    }
+    if bot.browser == nil {
+        t.Error("browser was not launched")
+    }
+}
+
+func TestLaunchTimeout(t *testing.T) {
+    bot, _ := newStubBrowserBot(t, "hang", WithLaunchTimeout(300*time.Millisecond))
+    err := bot.StartServer("127.0.0.1", 0)
+    var launchErr *BrowserLaunchError
+    if !errors.As(err, &launchErr) {
+        t.Fatalf("StartServer = %v, want *BrowserLaunchError", err)
+    }
+    if !strings.Contains(launchErr.Error(), "not ready within") || !strings.Contains(launchErr.Stderr, "stub browser starting") {
+        t.Errorf("launch error = %v", launchErr)
+    }
+    if bot.browser != nil {
+        t.Error("timed out browser is still recorded")
+    }
+    if profiles := profileDirs(t); len(profiles) != 0 {
+        t.Errorf("temp profile dirs left behind: %v", profiles)
+    }
+}
+
+func TestLaunchCrash(t *testing.T) {
+    bot, _ := newStubBrowserBot(t, "crash")
+    err := bot.StartServer("127.0.0.1", 0)
+    var launchErr *BrowserLaunchError
+    if !errors.As(err, &launchErr) {
+        t.Fatalf("StartServer = %v, want *BrowserLaunchError", err)
+    }
+    if !strings.Contains(launchErr.Stderr, "cannot open display") {
+        t.Errorf("stderr = %q", launchErr.Stderr)
+    }
+    if profiles := profileDirs(t); len(profiles) != 0 {
+        t.Errorf("temp profile dirs left behind: %v", profiles)
+    }
+}
+
+func TestStartServerCleansUpWhenPreparationFails(t *testing.T) {
+    // 下载目录的父路径是一个文件，创建下载目录会失败
+    blocker := filepath.Join(t.TempDir(), "file")
+    if err := os.WriteFile(blocker, nil, 0644); err != nil {
+        t.Fatal(err)
+    }
+    bot, _ := newStubBrowserBot(t, "ok", WithDownloadDir(filepath.Join(blocker, "downloads")))
+    if err := bot.StartServer("127.0.0.1", 0); err == nil {
+        t.Fatal("StartServer should fail")
+    }
+    if bot.browser != nil {
+        t.Error("browser launched although preparation failed")
+    }
+    if profiles := profileDirs(t); len(profiles) != 0 {
+        t.Errorf("temp profile dirs left behind: %v", profiles)
+    }
+}
+
+func TestStartServerAttachesWithoutTouchingProfile(t *testing.T) {
+    // 已经有浏览器在调试端口监听
+    l, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer l.Close()
+    go http.Serve(l, http.NotFoundHandler())
+    port := l.Addr().(*net.TCPAddr).Port
+
+    profile := t.TempDir()
+    downloads := filepath.Join(t.TempDir(), "downloads")
+    bot, _ := newStubBrowserBot(t, "ok",
+        WithDebugPort(port),
+        WithUserDataDir(profile),
+        WithDownloadDir(downloads),
+    )
+    if err := bot.StartServer("127.0.0.1", 0); err != nil {
+        t.Fatal(err)
+    }
+    if bot.browser != nil {
+        t.Error("a new browser was launched instead of attaching")
+    }
+    entries, err := os.ReadDir(profile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(entries) != 0 {
+        t.Errorf("attached browser profile was modified: %v", entries)
+    }
+    if info, err := os.Stat(downloads); err != nil || !info.IsDir() {
+        t.Errorf("download dir not created: %v", err)
+    }
+    if err := bot.StopServer(); err != nil {
+        t.Fatal(err)
+    }
+    if !portReady(port) {
+        t.Error("StopServer closed a browser it does not own")
+    }
+}
+
+func TestTailBufferEndpoint(t *testing.T) {
+    buf := &tailBuffer{limit: 32}
+    // 调试地址可能被拆成多次写入
+    buf.Write([]byte("noise\nDevTools listen"))
+    if buf.Endpoint() != "" {
+        t.Errorf("endpoint found in partial line: %q", buf.Endpoint())
+    }
+    buf.Write([]byte("ing on ws://127.0.0.1:41234/devtools/browser/abc\n"))
+    if got := buf.Endpoint(); got != "ws://127.0.0.1:41234/devtools/browser/abc" {
+        t.Errorf("endpoint = %q", got)
+    }
+    if len(buf.String()) > 32 {
+        t.Errorf("tail buffer kept %d bytes", len(buf.String()))
+    }
+    if port, err := endpointPort(buf.Endpoint()); err != nil || port != 41234 {
+        t.Errorf("endpointPort = %d, %v", port, err)
+    }
+    // 写入停在端口号中间时不能记录截断的地址
+    buf = &tailBuffer{limit: 32}
+    buf.Write([]byte("DevTools listening on ws://127.0.0.1:4"))
+    if buf.Endpoint() != "" {
+        t.Errorf("endpoint found in truncated url: %q", buf.Endpoint())
+    }
+    buf.Write([]byte("1234/devtools/browser/abc\r\nmore noise"))
+    if port, err := endpointPort(buf.Endpoint()); err != nil || port != 41234 {
+        t.Errorf("endpoint after split write = %q, port %d, %v", buf.Endpoint(), port, err)
+    }
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package webbot
+
+import (
+    "os/exec"
+    "syscall"
+)
+
+// setProcessGroup 让浏览器进程成为新进程组的组长，便于结束整个进程树
+func setProcessGroup(cmd *exec.Cmd) {
+    cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessTree 结束浏览器所在进程组中的所有进程
+func killProcessTree(cmd *exec.Cmd) error {
+    if cmd.Process == nil {
+        return nil
+    }
+    if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package webbot
+
+import (
+    "os/exec"
+    "strconv"
+)
+
+// setProcessGroup 在Windows上不需要额外设置，进程树由taskkill结束
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessTree 使用taskkill结束浏览器及其所有子进程
+func killProcessTree(cmd *exec.Cmd) error {
+    if cmd.Process == nil {
+        return nil
+    }
+    if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+        // 进程可能已经退出，退回到只结束主进程
+        return cmd.Process.Kill()
+    }
+    return nil
+}
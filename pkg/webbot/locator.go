@@ -0,0 +1,189 @@
+package webbot
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// Strategy 表示元素定位策略
+// 每个By定位器都明确声明自己使用的策略
+// 这样驱动程序不需要猜测选择器字符串是CSS还是XPath
+type Strategy string
+
+const (
+    StrategyCSS             Strategy = "css"
+    StrategyXPath           Strategy = "xpath"
+    StrategyID              Strategy = "id"
+    StrategyName            Strategy = "name"
+    StrategyLinkText        Strategy = "link"
+    StrategyPartialLinkText Strategy = "partiallink"
+    StrategyText            Strategy = "text"
+    StrategyRole            Strategy = "role"
+    StrategyLabel           Strategy = "label"
+    StrategyNth             Strategy = "nth"
+)
+
+// By 表示一个类型化的元素定位器
+// Strategy: 定位策略
+// Value: 定位值，如CSS选择器、XPath表达式、ID等
+// AccessibleName: 仅用于Role策略，表示ARIA可访问名称，可以为空
+// 定位器可以通过Find方法链式组合，从而在某个元素内部查找子元素
+// 例如：webbot.CSS("#login-form").Find(webbot.Name("username"))
+type By struct {
+    Strategy       Strategy
+    Value          string
+    AccessibleName string
+    parent         *By
+}
+
+// CSS 创建一个CSS选择器定位器
+func CSS(selector string) By {
+    return By{Strategy: StrategyCSS, Value: selector}
+}
+
+// XPath 创建一个XPath定位器
+func XPath(expr string) By {
+    return By{Strategy: StrategyXPath, Value: expr}
+}
+
+// ID 创建一个按id属性定位的定位器
+func ID(id string) By {
+    return By{Strategy: StrategyID, Value: id}
+}
+
+// Name 创建一个按name属性定位的定位器
+func Name(name string) By {
+    return By{Strategy: StrategyName, Value: name}
+}
+
+// LinkText 创建一个按链接完整文本定位的定位器
+func LinkText(text string) By {
+    return By{Strategy: StrategyLinkText, Value: text}
+}
+
+// PartialLinkText 创建一个按链接部分文本定位的定位器
+func PartialLinkText(text string) By {
+    return By{Strategy: StrategyPartialLinkText, Value: text}
+}
+
+// Text 创建一个按可见文本定位的定位器
+func Text(text string) By {
+    return By{Strategy: StrategyText, Value: text}
+}
+
+// Role 创建一个按ARIA角色定位的定位器
+// role: ARIA角色，如"button"、"textbox"
+// name: 可访问名称，为空表示不限制
+func Role(role string, name string) By {
+    return By{Strategy: StrategyRole, Value: role, AccessibleName: name}
+}
+
+// Label 创建一个按ARIA标签或关联label文本定位的定位器
+func Label(label string) By {
+    return By{Strategy: StrategyLabel, Value: label}
+}
+
+// Find 返回一个在当前定位器匹配的元素内部查找child的新定位器
+// 原定位器不会被修改，因此同一个父定位器可以派生多个子定位器
+func (by By) Find(child By) By {
+    parent := by
+    if child.parent != nil {
+        // 子定位器本身已经是链，需要把整条链挂到当前定位器下
+        root := parent.Find(*child.parent)
+        child.parent = &root
+        return child
+    }
+    child.parent = &parent
+    return child
+}
+
+// Nth 返回一个只匹配当前定位器第index个结果(从0开始)的新定位器
+// 例如：webbot.CSS(".row").Nth(2)的文本形式为"css=.row >> nth=2"
+func (by By) Nth(index int) By {
+    return by.Find(By{Strategy: StrategyNth, Value: strconv.Itoa(index)})
+}
+
+// Parent 返回父定位器，如果不是链式定位器则返回false
+func (by By) Parent() (By, bool) {
+    if by.parent == nil {
+        return By{}, false
+    }
+    return *by.parent, true
+}
+
+// Chain 返回从最外层到当前定位器的完整定位链
+func (by By) Chain() []By {
+    var chain []By
+    for cur := &by; cur != nil; cur = cur.parent {
+        step := *cur
+        step.parent = nil
+        chain = append([]By{step}, chain...)
+    }
+    return chain
+}
+
+// IsZero 判断定位器是否为空
+func (by By) IsZero() bool {
+    return by.Strategy == "" && by.Value == "" && by.parent == nil
+}
+
+// validate 检查定位链中的每一步是否都指定了策略和值
+func (by By) validate() error {
+    for _, step := range by.Chain() {
+        if step.Strategy == "" || step.Value == "" {
+            return fmt.Errorf("invalid locator %q: strategy and value are required", by.String())
+        }
+    }
+    return nil
+}
+
+// String 返回定位器的文本形式，与ParseBy互逆
+// 链式定位器用" >> "连接，例如：css=#form >> name=username
+func (by By) String() string {
+    steps := by.Chain()
+    parts := make([]string, 0, len(steps))
+    for _, step := range steps {
+        value := step.Value
+        if step.Strategy == StrategyRole && step.AccessibleName != "" {
+            value += "|" + step.AccessibleName
+        }
+        parts = append(parts, string(step.Strategy)+"="+value)
+    }
+    return strings.Join(parts, " >> ")
+}
+
+// ParseBy 将文本形式的定位器解析为By
+// 格式为"策略=值"，如"css=#login"、"xpath=//input"、"role=button|提交"
+// 多个定位器可以用" >> "连接表示链式查找
+func ParseBy(s string) (By, error) {
+    var result By
+    for i, part := range strings.Split(s, " >> ") {
+        part = strings.TrimSpace(part)
+        idx := strings.Index(part, "=")
+        if idx <= 0 {
+            return By{}, fmt.Errorf("invalid locator %q: missing strategy", part)
+        }
+        step := By{Strategy: Strategy(part[:idx]), Value: part[idx+1:]}
+        switch step.Strategy {
+        case StrategyCSS, StrategyXPath, StrategyID, StrategyName, StrategyLinkText,
+            StrategyPartialLinkText, StrategyText, StrategyLabel:
+        case StrategyNth:
+            if n, err := strconv.Atoi(step.Value); err != nil || n < 0 || i == 0 {
+                return By{}, fmt.Errorf("invalid locator %q: nth must follow another locator and be a non-negative integer", part)
+            }
+        case StrategyRole:
+            if role, name, ok := strings.Cut(step.Value, "|"); ok {
+                step.Value, step.AccessibleName = role, name
+            }
+        default:
+            return By{}, fmt.Errorf("invalid locator %q: unknown strategy %q", part, step.Strategy)
+        }
+        if i == 0 {
+            result = step
+        } else {
+            result = result.Find(step)
+        }
+    }
+    return result, nil
+}
@@ -0,0 +1,353 @@
+package webbot
+
+import (
+    "errors"
+    "fmt"
+    "regexp"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ErrRouteHandled 表示同一个请求被重复处理
+var ErrRouteHandled = errors.New("webbot: route is already handled")
+
+// ResponseTimeoutError 表示在指定时间内没有收到匹配的响应
+type ResponseTimeoutError struct {
+    Timeout time.Duration
+}
+
+func (e *ResponseTimeoutError) Error() string {
+    return fmt.Sprintf("webbot: no matching response within %s", e.Timeout)
+}
+
+// Request 表示页面发出的一个网络请求
+// ID: 驱动程序分配的请求ID，用于关联请求和响应
+// ResourceType: 资源类型，如document、xhr、fetch、script、image等
+type Request struct {
+    ID           string
+    URL          string
+    Method       string
+    Headers      map[string]string
+    PostData     string
+    ResourceType string
+}
+
+// Response 表示一个网络响应
+// Body只在驱动程序能够获取响应体时填充，例如被拦截并完整读取的响应
+type Response struct {
+    RequestID  string
+    URL        string
+    Status     int
+    StatusText string
+    Headers    map[string]string
+    MimeType   string
+    Body       []byte
+}
+
+// NetworkEventType 表示网络事件的类型
+type NetworkEventType string
+
+const (
+    NetworkRequest  NetworkEventType = "request"
+    NetworkResponse NetworkEventType = "response"
+    NetworkFailed   NetworkEventType = "failed"
+)
+
+// NetworkEvent 表示页面网络流量中的一个事件
+// Request字段始终有效，Response只在NetworkResponse事件中有效
+// ErrorText只在NetworkFailed事件中有效
+type NetworkEvent struct {
+    Type      NetworkEventType
+    Time      time.Time
+    Request   Request
+    Response  Response
+    ErrorText string
+}
+
+// ContinueOptions 表示继续请求时对请求的修改
+// 各字段为空表示保持原值，Headers会与原请求头合并
+type ContinueOptions struct {
+    URL      string
+    Method   string
+    Headers  map[string]string
+    PostData string
+}
+
+// FulfillOptions 表示使用固定内容响应请求
+// Status为0时使用200
+type FulfillOptions struct {
+    Status      int
+    Headers     map[string]string
+    ContentType string
+    Body        []byte
+}
+
+// 常用的中止原因，与浏览器的网络错误码对应
+const (
+    AbortFailed          = "failed"
+    AbortAborted         = "aborted"
+    AbortAccessDenied    = "accessdenied"
+    AbortBlockedByClient = "blockedbyclient"
+    AbortTimedOut        = "timedout"
+)
+
+// Route 表示一个被拦截的请求
+// 路由处理函数必须调用Continue、Fulfill或Abort之一
+// 如果处理函数返回时都没有调用，请求会按原样继续
+type Route struct {
+    Request Request
+
+    bot     *webBotImpl
+    mu      sync.Mutex
+    handled bool
+}
+
+// Continue 继续发送请求
+// options: 对请求的修改，零值表示不修改
+func (r *Route) Continue(options ContinueOptions) error {
+    if err := r.markHandled(); err != nil {
+        return err
+    }
+    headers := options.Headers
+    if headers != nil {
+        merged := make(map[string]string, len(r.Request.Headers)+len(headers))
+        for k, v := range r.Request.Headers {
+            merged[k] = v
+        }
+        for k, v := range headers {
+            merged[k] = v
+        }
+        headers = merged
+    }
+    return r.bot.resolveRoute(r.Request.ID, "continue", map[string]interface{}{
+        "url":      options.URL,
+        "method":   options.Method,
+        "headers":  headers,
+        "postData": options.PostData,
+    })
+}
+
+// Fulfill 使用固定内容响应请求，请求不会发送到服务器
+func (r *Route) Fulfill(options FulfillOptions) error {
+    if err := r.markHandled(); err != nil {
+        return err
+    }
+    status := options.Status
+    if status == 0 {
+        status = 200
+    }
+    headers := map[string]string{}
+    for k, v := range options.Headers {
+        headers[k] = v
+    }
+    if options.ContentType != "" {
+        headers["Content-Type"] = options.ContentType
+    }
+    return r.bot.resolveRoute(r.Request.ID, "fulfill", map[string]interface{}{
+        "status":  status,
+        "headers": headers,
+        "body":    options.Body,
+    })
+}
+
+// Abort 中止请求
+// reason: 中止原因，如AbortFailed、AbortBlockedByClient，为空时使用AbortFailed
+func (r *Route) Abort(reason string) error {
+    if err := r.markHandled(); err != nil {
+        return err
+    }
+    if reason == "" {
+        reason = AbortFailed
+    }
+    return r.bot.resolveRoute(r.Request.ID, "abort", map[string]interface{}{
+        "reason": reason,
+    })
+}
+
+// markHandled 标记请求已被处理，防止重复处理
+func (r *Route) markHandled() error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    if r.handled {
+        return ErrRouteHandled
+    }
+    r.handled = true
+    return nil
+}
+
+// RouteHandler 定义路由处理函数
+type RouteHandler func(route *Route)
+
+// route 表示一个已注册的路由
+type route struct {
+    pattern string
+    re      *regexp.Regexp
+    handler RouteHandler
+}
+
+// compileURLPattern 将URL通配符模式编译为正则表达式
+// **匹配任意字符，*匹配除/以外的任意字符，?匹配单个字符
+// 以"re:"开头的模式直接作为正则表达式使用
+func compileURLPattern(pattern string) (*regexp.Regexp, error) {
+    if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+        return regexp.Compile(expr)
+    }
+    var sb strings.Builder
+    sb.WriteString("^")
+    for i := 0; i < len(pattern); i++ {
+        c := pattern[i]
+        switch c {
+        case '*':
+            if i+1 < len(pattern) && pattern[i+1] == '*' {
+                sb.WriteString(".*")
+                i++
+            } else {
+                sb.WriteString("[^/]*")
+            }
+        case '?':
+            sb.WriteString(".")
+        default:
+            sb.WriteString(regexp.QuoteMeta(string(c)))
+        }
+    }
+    sb.WriteString("$")
+    return regexp.Compile(sb.String())
+}
+
+// sendRouteResult 将请求的处理结果发送给驱动程序
+// 定义为变量，测试时可以替换为模拟的驱动程序
+var sendRouteResult = func(b *webBotImpl, requestID string, action string, params map[string]interface{}) error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// resolveRoute 将请求的处理结果发送给驱动程序
+// action: "continue"、"fulfill"或"abort"
+func (b *webBotImpl) resolveRoute(requestID string, action string, params map[string]interface{}) error {
+    return sendRouteResult(b, requestID, action, params)
+}
+
+// setInterception 通知驱动程序开启或关闭请求拦截
+func (b *webBotImpl) setInterception(enabled bool) error {
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的Route方法
+func (b *webBotImpl) Route(pattern string, handler RouteHandler) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if handler == nil {
+        return errors.New("webbot: route handler is nil")
+    }
+    re, err := compileURLPattern(pattern)
+    if err != nil {
+        return fmt.Errorf("invalid url pattern %q: %w", pattern, err)
+    }
+    b.mu.Lock()
+    b.routes = append(b.routes, route{pattern: pattern, re: re, handler: handler})
+    first := len(b.routes) == 1
+    b.mu.Unlock()
+    if first {
+        return b.setInterception(true)
+    }
+    return nil
+}
+
+// 实现WebBot接口的Unroute方法
+func (b *webBotImpl) Unroute(pattern string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    b.mu.Lock()
+    kept := b.routes[:0]
+    for _, r := range b.routes {
+        if r.pattern != pattern {
+            kept = append(kept, r)
+        }
+    }
+    b.routes = kept
+    empty := len(b.routes) == 0
+    b.mu.Unlock()
+    if empty {
+        return b.setInterception(false)
+    }
+    return nil
+}
+
+// 实现WebBot接口的SubscribeNetwork方法
+func (b *webBotImpl) SubscribeNetwork() (<-chan NetworkEvent, func()) {
+    return b.networkEvents.subscribe()
+}
+
+// 实现WebBot接口的WaitForResponse方法
+func (b *webBotImpl) WaitForResponse(match func(Response) bool, trigger func() error, timeout time.Duration) (Response, error) {
+    if err := b.checkPageError(); err != nil {
+        return Response{}, err
+    }
+    // 先订阅再执行trigger，避免错过trigger期间到达的响应
+    events, cancel := b.SubscribeNetwork()
+    defer cancel()
+    if trigger != nil {
+        if err := trigger(); err != nil {
+            return Response{}, err
+        }
+    }
+    timer := time.NewTimer(timeout)
+    defer timer.Stop()
+    for {
+        select {
+        case event, ok := <-events:
+            if !ok {
+                return Response{}, errors.New("webbot: network event stream closed")
+            }
+            if event.Type == NetworkResponse && (match == nil || match(event.Response)) {
+                return event.Response, nil
+            }
+        case <-timer.C:
+            return Response{}, &ResponseTimeoutError{Timeout: timeout}
+        }
+    }
+}
+
+// onRequestPaused 在驱动程序拦截到请求时调用
+// 后注册的路由优先匹配，未匹配任何路由的请求按原样继续
+func (b *webBotImpl) onRequestPaused(req Request) error {
+    b.mu.Lock()
+    var handler RouteHandler
+    for i := len(b.routes) - 1; i >= 0; i-- {
+        if b.routes[i].re.MatchString(req.URL) {
+            handler = b.routes[i].handler
+            break
+        }
+    }
+    b.mu.Unlock()
+
+    r := &Route{Request: req, bot: b}
+    if handler != nil {
+        handler(r)
+    }
+    r.mu.Lock()
+    handled := r.handled
+    r.mu.Unlock()
+    if !handled {
+        return r.Continue(ContinueOptions{})
+    }
+    return nil
+}
+
+// onNetworkEvent 在驱动程序上报请求、响应或失败事件时调用
+func (b *webBotImpl) onNetworkEvent(event NetworkEvent) {
+    if event.Time.IsZero() {
+        event.Time = time.Now()
+    }
+    // HAR录制器直接接收事件，订阅通道缓冲满时会丢弃事件
+    if b.harRecorder != nil {
+        b.harRecorder.record(event)
+    }
+    b.networkEvents.publish(event)
+}
@@ -0,0 +1,301 @@
+package webbot
+
+import (
+    "errors"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestCompileURLPattern(t *testing.T) {
+    tests := []struct {
+        pattern string
+        url     string
+        want    bool
+    }{
+        {"**/api/**", "https://example.com/api/users/1", true},
+        {"**/api/*", "https://example.com/api/users", true},
+        {"**/api/*", "https://example.com/api/users/1", false},
+        {"https://example.com/*.png", "https://example.com/logo.png", true},
+        {"https://example.com/*.png", "https://example.com/img/logo.png", false},
+        {"https://example.com/v?/users", "https://example.com/v2/users", true},
+        {"https://example.com/v?/users", "https://example.com/v10/users", false},
+        {"https://example.com/a+b?q=1", "https://example.com/a+bXq=1", true},
+        {"https://example.com/a+b", "https://example.com/aab", false},
+        {"https://example.com/", "https://example.com/index.html", false},
+        {"**", "data:text/plain,hello", true},
+        {`re:\.json(\?.*)?$`, "https://example.com/data.json?v=2", true},
+        {`re:\.json(\?.*)?$`, "https://example.com/data.jsonp", false},
+    }
+    for _, tt := range tests {
+        re, err := compileURLPattern(tt.pattern)
+        if err != nil {
+            t.Fatalf("compileURLPattern(%q): %v", tt.pattern, err)
+        }
+        if got := re.MatchString(tt.url); got != tt.want {
+            t.Errorf("pattern %q on %q = %v, want %v", tt.pattern, tt.url, got, tt.want)
+        }
+    }
+    if _, err := compileURLPattern("re:("); err == nil {
+        t.Error("compileURLPattern(\"re:(\") should fail")
+    }
+}
+
+// resolvedRoute 是fakeDriver记录的一次路由处理结果
+type resolvedRoute struct {
+    action string
+    status int
+    header http.Header
+    body   string
+    reason string
+}
+
+// fakeDriver 模拟驱动程序执行路由处理结果
+// continue时真正向httptest服务器发送请求，fulfill和abort时不访问服务器
+type fakeDriver struct {
+    requests map[string]Request
+    mu       sync.Mutex
+    results  map[string]resolvedRoute
+}
+
+func (d *fakeDriver) resolve(requestID string, action string, params map[string]interface{}) error {
+    req := d.requests[requestID]
+    result := resolvedRoute{action: action}
+    switch action {
+    case "continue":
+        url, method := req.URL, req.Method
+        if u, _ := params["url"].(string); u != "" {
+            url = u
+        }
+        if m, _ := params["method"].(string); m != "" {
+            method = m
+        }
+        headers := req.Headers
+        if h, _ := params["headers"].(map[string]string); h != nil {
+            headers = h
+        }
+        httpReq, err := http.NewRequest(method, url, strings.NewReader(req.PostData))
+        if err != nil {
+            return err
+        }
+        for k, v := range headers {
+            httpReq.Header.Set(k, v)
+        }
+        resp, err := http.DefaultClient.Do(httpReq)
+        if err != nil {
+            return err
+        }
+        defer resp.Body.Close()
+        body, _ := io.ReadAll(resp.Body)
+        result.status, result.header, result.body = resp.StatusCode, resp.Header, string(body)
+    case "fulfill":
+        result.status = params["status"].(int)
+        result.header = http.Header{}
+        for k, v := range params["headers"].(map[string]string) {
+            result.header.Set(k, v)
+        }
+        result.body = string(params["body"].([]byte))
+    case "abort":
+        result.reason = params["reason"].(string)
+    }
+    d.mu.Lock()
+    d.results[requestID] = result
+    d.mu.Unlock()
+    return nil
+}
+
+func TestRouteAgainstHTTPServer(t *testing.T) {
+    var hits sync.Map
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        hits.Store(r.URL.Path, r.Header.Clone())
+        w.Header().Set("Content-Type", "text/plain")
+        io.WriteString(w, r.Method+" "+r.URL.Path)
+    }))
+    defer srv.Close()
+
+    driver := &fakeDriver{requests: map[string]Request{}, results: map[string]resolvedRoute{}}
+    restore := sendRouteResult
+    sendRouteResult = func(_ *webBotImpl, requestID, action string, params map[string]interface{}) error {
+        return driver.resolve(requestID, action, params)
+    }
+    defer func() { sendRouteResult = restore }()
+    bot := &webBotImpl{}
+
+    if err := bot.Route("**/api/**", func(r *Route) {
+        if err := r.Continue(ContinueOptions{Headers: map[string]string{"X-Test": "1"}}); err != nil {
+            t.Errorf("Continue: %v", err)
+        }
+        if err := r.Abort(""); !errors.Is(err, ErrRouteHandled) {
+            t.Errorf("second resolution = %v, want ErrRouteHandled", err)
+        }
+    }); err != nil {
+        t.Fatal(err)
+    }
+    if err := bot.Route("**/api/mock", func(r *Route) {
+        r.Fulfill(FulfillOptions{ContentType: "application/json", Body: []byte(`{"mock":true}`)})
+    }); err != nil {
+        t.Fatal(err)
+    }
+    if err := bot.Route("**/ads/**", func(r *Route) {
+        r.Abort(AbortBlockedByClient)
+    }); err != nil {
+        t.Fatal(err)
+    }
+    if err := bot.Route("**/redirect", func(r *Route) {
+        r.Continue(ContinueOptions{URL: srv.URL + "/api/rewritten", Method: http.MethodPost})
+    }); err != nil {
+        t.Fatal(err)
+    }
+
+    requests := []Request{
+        {ID: "1", URL: srv.URL + "/api/users", Method: http.MethodGet, Headers: map[string]string{"Accept": "text/plain"}},
+        {ID: "2", URL: srv.URL + "/api/mock", Method: http.MethodGet},
+        {ID: "3", URL: srv.URL + "/ads/banner.js", Method: http.MethodGet},
+        {ID: "4", URL: srv.URL + "/static/app.js", Method: http.MethodGet},
+        {ID: "5", URL: srv.URL + "/redirect", Method: http.MethodGet},
+    }
+    for _, req := range requests {
+        driver.requests[req.ID] = req
+        if err := bot.onRequestPaused(req); err != nil {
+            t.Fatalf("onRequestPaused(%s): %v", req.URL, err)
+        }
+    }
+
+    // Continue合并请求头后发送到服务器
+    if got := driver.results["1"]; got.action != "continue" || got.body != "GET /api/users" {
+        t.Errorf("continued request = %+v", got)
+    }
+    if h, ok := hits.Load("/api/users"); !ok {
+        t.Error("continued request did not reach the server")
+    } else if header := h.(http.Header); header.Get("X-Test") != "1" || header.Get("Accept") != "text/plain" {
+        t.Errorf("server saw headers %v, want X-Test and Accept", header)
+    }
+
+    // 后注册的路由优先，Fulfill不访问服务器
+    if got := driver.results["2"]; got.action != "fulfill" || got.status != 200 ||
+        got.header.Get("Content-Type") != "application/json" || got.body != `{"mock":true}` {
+        t.Errorf("fulfilled request = %+v", got)
+    }
+    if _, ok := hits.Load("/api/mock"); ok {
+        t.Error("fulfilled request reached the server")
+    }
+
+    if got := driver.results["3"]; got.action != "abort" || got.reason != AbortBlockedByClient {
+        t.Errorf("aborted request = %+v", got)
+    }
+    if _, ok := hits.Load("/ads/banner.js"); ok {
+        t.Error("aborted request reached the server")
+    }
+
+    // 没有匹配的路由时按原样继续
+    if got := driver.results["4"]; got.action != "continue" || got.body != "GET /static/app.js" {
+        t.Errorf("unrouted request = %+v", got)
+    }
+
+    if got := driver.results["5"]; got.action != "continue" || got.body != "POST /api/rewritten" {
+        t.Errorf("rewritten request = %+v", got)
+    }
+
+    // 删除路由后请求不再被拦截
+    if err := bot.Unroute("**/api/mock"); err != nil {
+        t.Fatal(err)
+    }
+    driver.requests["6"] = Request{ID: "6", URL: srv.URL + "/api/mock", Method: http.MethodGet}
+    if err := bot.onRequestPaused(driver.requests["6"]); err != nil {
+        t.Fatal(err)
+    }
+    if got := driver.results["6"]; got.action != "continue" || got.body != "GET /api/mock" {
+        t.Errorf("request after Unroute = %+v", got)
+    }
+}
+
+func TestRouteRejectsInvalidInput(t *testing.T) {
+    bot := &webBotImpl{}
+    if err := bot.Route("**", nil); err == nil {
+        t.Error("Route with nil handler should fail")
+    }
+    if err := bot.Route("re:[", func(*Route) {}); err == nil {
+        t.Error("Route with invalid regexp should fail")
+    }
+}
+
+func TestBroadcaster(t *testing.T) {
+    var bc broadcaster[int]
+    a, cancelA := bc.subscribe()
+    b, cancelB := bc.subscribe()
+    bc.publish(1)
+    if got := <-a; got != 1 {
+        t.Errorf("subscriber a got %d, want 1", got)
+    }
+    if got := <-b; got != 1 {
+        t.Errorf("subscriber b got %d, want 1", got)
+    }
+
+    // 取消后通道关闭，不再收到事件，重复取消是安全的
+    cancelA()
+    cancelA()
+    if _, ok := <-a; ok {
+        t.Error("cancelled channel is still open")
+    }
+    bc.publish(2)
+    if got := <-b; got != 2 {
+        t.Errorf("subscriber b got %d, want 2", got)
+    }
+
+    // 缓冲满时丢弃事件而不是阻塞发布者
+    done := make(chan struct{})
+    go func() {
+        for i := 0; i < subscriberBuffer+10; i++ {
+            bc.publish(i)
+        }
+        close(done)
+    }()
+    select {
+    case <-done:
+    case <-time.After(5 * time.Second):
+        t.Fatal("publish blocked on a full subscriber")
+    }
+    if len(b) != subscriberBuffer {
+        t.Errorf("buffered %d events, want %d", len(b), subscriberBuffer)
+    }
+
+    bc.closeAll()
+    for range b {
+    }
+    cancelB()
+}
+
+func TestWaitForResponse(t *testing.T) {
+    bot := &webBotImpl{}
+    want := Response{RequestID: "7", URL: "https://example.com/api/login", Status: 200}
+    got, err := bot.WaitForResponse(func(r Response) bool {
+        return strings.HasSuffix(r.URL, "/login")
+    }, func() error {
+        // trigger执行期间到达的事件不会丢失
+        bot.onNetworkEvent(NetworkEvent{Type: NetworkRequest, Request: Request{ID: "7", URL: want.URL}})
+        bot.onNetworkEvent(NetworkEvent{Type: NetworkResponse, Response: Response{URL: "https://example.com/app.js"}})
+        bot.onNetworkEvent(NetworkEvent{Type: NetworkResponse, Response: want})
+        return nil
+    }, time.Second)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.RequestID != want.RequestID || got.Status != want.Status {
+        t.Errorf("WaitForResponse = %+v, want %+v", got, want)
+    }
+
+    _, err = bot.WaitForResponse(nil, nil, 50*time.Millisecond)
+    var timeout *ResponseTimeoutError
+    if !errors.As(err, &timeout) {
+        t.Errorf("WaitForResponse without events = %v, want *ResponseTimeoutError", err)
+    }
+
+    triggerErr := errors.New("click failed")
+    if _, err := bot.WaitForResponse(nil, func() error { return triggerErr }, time.Second); !errors.Is(err, triggerErr) {
+        t.Errorf("WaitForResponse with failing trigger = %v", err)
+    }
+}
@@ -0,0 +1,354 @@
+package webbot
+
+import (
+    "errors"
+    "fmt"
+    "reflect"
+    "time"
+)
+
+// pagePollInterval 是页面对象等待元素出现时的轮询间隔
+const pagePollInterval = 100 * time.Millisecond
+
+// ElementNotFoundError 表示在隐式等待时间内没有找到元素
+type ElementNotFoundError struct {
+    Locator By
+    Timeout time.Duration
+}
+
+func (e *ElementNotFoundError) Error() string {
+    return fmt.Sprintf("webbot: element %s not found within %s", e.Locator, e.Timeout)
+}
+
+// Element 是页面对象中的延迟元素句柄
+// 它只保存定位器，每次使用时重新查找元素，并在元素未出现时进行隐式等待
+// 因此页面刷新或重新渲染后句柄依然有效
+type Element struct {
+    bot     WebBot
+    by      By
+    timeout time.Duration
+}
+
+// Locator 返回元素的完整定位器
+func (e Element) Locator() By {
+    return e.by
+}
+
+// Resolve 查找元素，元素未出现时在隐式等待时间内重试
+// 超时返回*ElementNotFoundError
+func (e Element) Resolve() (WebElement, error) {
+    if e.bot == nil {
+        return WebElement{}, errors.New("webbot: element is not bound, call Bind first")
+    }
+    elements, err := waitForElements(e.bot, e.by, e.timeout)
+    if err != nil {
+        return WebElement{}, err
+    }
+    return elements[0], nil
+}
+
+// Exists 立即判断元素是否存在，不进行等待
+func (e Element) Exists() (bool, error) {
+    if e.bot == nil {
+        return false, errors.New("webbot: element is not bound, call Bind first")
+    }
+    elements, err := e.bot.FindElements(e.by)
+    return len(elements) > 0, err
+}
+
+// Click 等待元素出现后点击
+func (e Element) Click() error {
+    if _, err := e.Resolve(); err != nil {
+        return err
+    }
+    return e.bot.ClickElement(e.by)
+}
+
+// Type 等待元素出现后输入文本
+func (e Element) Type(text string) error {
+    if _, err := e.Resolve(); err != nil {
+        return err
+    }
+    return e.bot.InputText(e.by, text)
+}
+
+// Clear 等待元素出现后清空内容
+func (e Element) Clear() error {
+    if _, err := e.Resolve(); err != nil {
+        return err
+    }
+    return e.bot.ClearElement(e.by)
+}
+
+// Fill 等待元素出现后清空内容并输入文本
+func (e Element) Fill(text string) error {
+    if err := e.Clear(); err != nil {
+        return err
+    }
+    return e.bot.InputText(e.by, text)
+}
+
+// Text 等待元素出现后获取可见文本
+func (e Element) Text() (string, error) {
+    if _, err := e.Resolve(); err != nil {
+        return "", err
+    }
+    return e.bot.GetElementText(e.by)
+}
+
+// Attribute 等待元素出现后获取属性值
+func (e Element) Attribute(name string) (string, error) {
+    if _, err := e.Resolve(); err != nil {
+        return "", err
+    }
+    return e.bot.GetElementAttribute(e.by, name)
+}
+
+// Value 等待元素出现后获取表单控件的值
+func (e Element) Value() (string, error) {
+    if _, err := e.Resolve(); err != nil {
+        return "", err
+    }
+    return e.bot.GetElementValue(e.by)
+}
+
+// Select 等待下拉框出现后选择选项
+func (e Element) Select(option string) error {
+    if _, err := e.Resolve(); err != nil {
+        return err
+    }
+    return e.bot.SelectOption(e.by, option)
+}
+
+// IsSelected 等待元素出现后判断是否被选中
+func (e Element) IsSelected() (bool, error) {
+    if _, err := e.Resolve(); err != nil {
+        return false, err
+    }
+    return e.bot.IsElementSelected(e.by)
+}
+
+// Elements 是页面对象中的延迟元素列表
+// 例如：Rows webbot.Elements `aibote:"css=table tr"`
+type Elements struct {
+    bot     WebBot
+    by      By
+    timeout time.Duration
+}
+
+// Locator 返回元素列表的完整定位器
+func (l Elements) Locator() By {
+    return l.by
+}
+
+// Count 立即返回当前匹配的元素数量，不进行等待
+func (l Elements) Count() (int, error) {
+    if l.bot == nil {
+        return 0, errors.New("webbot: elements are not bound, call Bind first")
+    }
+    elements, err := l.bot.FindElements(l.by)
+    return len(elements), err
+}
+
+// At 返回第index个(从0开始)元素的延迟句柄
+func (l Elements) At(index int) Element {
+    return Element{bot: l.bot, by: l.by.Nth(index), timeout: l.timeout}
+}
+
+// All 等待至少一个元素出现后，返回所有元素的延迟句柄
+func (l Elements) All() ([]Element, error) {
+    if l.bot == nil {
+        return nil, errors.New("webbot: elements are not bound, call Bind first")
+    }
+    found, err := waitForElements(l.bot, l.by, l.timeout)
+    if err != nil {
+        return nil, err
+    }
+    result := make([]Element, len(found))
+    for i := range found {
+        result[i] = l.At(i)
+    }
+    return result, nil
+}
+
+// Components 是页面对象中重复出现的组件列表
+// T是声明了aibote标签的组件结构体，每个组件内部的定位器以第i个匹配元素为根
+// 例如：Items webbot.Components[CartItem] `aibote:"css=.cart-item"`
+type Components[T any] struct {
+    bot     WebBot
+    by      By
+    timeout time.Duration
+}
+
+// Locator 返回组件根元素的定位器
+func (c Components[T]) Locator() By {
+    return c.by
+}
+
+// Count 立即返回当前匹配的组件数量，不进行等待
+func (c Components[T]) Count() (int, error) {
+    if c.bot == nil {
+        return 0, errors.New("webbot: components are not bound, call Bind first")
+    }
+    elements, err := c.bot.FindElements(c.by)
+    return len(elements), err
+}
+
+// At 返回第index个(从0开始)组件
+func (c Components[T]) At(index int) (T, error) {
+    var component T
+    err := bindValue(reflect.ValueOf(&component).Elem(), c.bot, c.by.Nth(index), true, c.timeout)
+    return component, err
+}
+
+// All 等待至少一个组件出现后，返回所有组件
+func (c Components[T]) All() ([]T, error) {
+    if c.bot == nil {
+        return nil, errors.New("webbot: components are not bound, call Bind first")
+    }
+    found, err := waitForElements(c.bot, c.by, c.timeout)
+    if err != nil {
+        return nil, err
+    }
+    result := make([]T, 0, len(found))
+    for i := range found {
+        component, err := c.At(i)
+        if err != nil {
+            return nil, err
+        }
+        result = append(result, component)
+    }
+    return result, nil
+}
+
+// bind 实现binder接口
+func (c *Components[T]) bind(bot WebBot, by By, timeout time.Duration) {
+    c.bot, c.by, c.timeout = bot, by, timeout
+}
+
+// bind 实现binder接口
+func (e *Element) bind(bot WebBot, by By, timeout time.Duration) {
+    e.bot, e.by, e.timeout = bot, by, timeout
+}
+
+// bind 实现binder接口
+func (l *Elements) bind(bot WebBot, by By, timeout time.Duration) {
+    l.bot, l.by, l.timeout = bot, by, timeout
+}
+
+// binder 由可以被Bind填充的页面对象字段类型实现
+type binder interface {
+    bind(bot WebBot, by By, timeout time.Duration)
+}
+
+// waitForElements 在timeout内轮询直到至少找到一个元素
+func waitForElements(bot WebBot, by By, timeout time.Duration) ([]WebElement, error) {
+    deadline := time.Now().Add(timeout)
+    for {
+        elements, err := bot.FindElements(by)
+        if err != nil {
+            return nil, err
+        }
+        if len(elements) > 0 {
+            return elements, nil
+        }
+        if time.Now().After(deadline) {
+            return nil, &ElementNotFoundError{Locator: by, Timeout: timeout}
+        }
+        time.Sleep(pagePollInterval)
+    }
+}
+
+// Bind 填充页面对象结构体中的元素句柄
+// bot: 用于查找和操作元素的WebBot
+// page: 页面对象结构体的指针
+// 字段通过aibote标签声明定位器，不带策略的文本按name属性定位，与FillForm一致，例如：
+//
+//    type LoginPage struct {
+//        Username webbot.Element                 `aibote:"css=#username"`
+//        Submit   webbot.Element                 `aibote:"role=button|登录"`
+//        Header   HeaderComponent                `aibote:"css=header"`
+//        Items    webbot.Components[ItemComponent] `aibote:"css=.item"`
+//    }
+//
+// 嵌套组件结构体中的定位器以组件自身的定位器为根，没有标签的组件与父结构体共用根
+// 字段也可以声明为*webbot.Element等指针类型，Bind会为其分配内存
+// 带标签的字段不是元素句柄或结构体时返回错误
+// 元素句柄是延迟的，使用时才查找元素，并按WithImplicitWait设置的时间进行隐式等待
+func Bind(bot WebBot, page interface{}) error {
+    v := reflect.ValueOf(page)
+    if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+        return fmt.Errorf("webbot: Bind expects a non-nil struct pointer, got %T", page)
+    }
+    timeout := 5 * time.Second
+    if impl, ok := bot.(*webBotImpl); ok {
+        timeout = time.Duration(impl.implicitWait * float64(time.Second))
+    }
+    return bindValue(v.Elem(), bot, By{}, false, timeout)
+}
+
+// bindValue 递归填充结构体中带aibote标签的字段
+// root: 当前结构体的根定位器，hasRoot为false时字段定位器直接从页面查找
+func bindValue(v reflect.Value, bot WebBot, root By, hasRoot bool, timeout time.Duration) error {
+    if v.Kind() != reflect.Struct {
+        return nil
+    }
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        sf := t.Field(i)
+        if !sf.IsExported() {
+            continue
+        }
+        field := v.Field(i)
+        tag, tagged := sf.Tag.Lookup(locatorTag)
+        if tag == "-" {
+            continue
+        }
+
+        by, scoped := root, hasRoot
+        if tagged {
+            local, err := parseLocatorTag(tag)
+            if err != nil {
+                return fmt.Errorf("field %s.%s: %w", t.Name(), sf.Name, err)
+            }
+            if hasRoot {
+                by = root.Find(local)
+            } else {
+                by = local
+            }
+            scoped = true
+        }
+
+        if b, ok := field.Addr().Interface().(binder); ok {
+            if !tagged {
+                return fmt.Errorf("field %s.%s: missing %s tag", t.Name(), sf.Name, locatorTag)
+            }
+            b.bind(bot, by, timeout)
+            continue
+        }
+
+        target := field
+        // 只为带标签的指针组件分配内存，避免自引用的结构体无限递归
+        if tagged && field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct {
+            if field.IsNil() {
+                field.Set(reflect.New(field.Type().Elem()))
+            }
+            // *webbot.Element、*webbot.Components[T]等指针字段直接绑定
+            if b, ok := field.Interface().(binder); ok {
+                b.bind(bot, by, timeout)
+                continue
+            }
+            target = field.Elem()
+        }
+        isStruct := target.Kind() == reflect.Struct && target.Type() != reflect.TypeOf(time.Time{})
+        if tagged && !isStruct {
+            return fmt.Errorf("field %s.%s: unsupported type %s for %s tag", t.Name(), sf.Name, sf.Type, locatorTag)
+        }
+        if isStruct {
+            if err := bindValue(target, bot, by, scoped, timeout); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
@@ -0,0 +1,84 @@
+package webbot
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+)
+
+// firefoxPrefLine 匹配user.js中的一行首选项设置，捕获首选项名称
+var firefoxPrefLine = regexp.MustCompile(`^\s*user_pref\(\s*"([^"]+)"`)
+
+// writeChromiumPrefs 将首选项合并写入Chrome和Edge默认配置的Preferences文件
+// 两边都是对象的键递归合并，其他键用prefs中的值替换，没有涉及的首选项保持不变
+func writeChromiumPrefs(userDataDir string, prefs map[string]interface{}) error {
+    profileDir := filepath.Join(userDataDir, "Default")
+    if err := os.MkdirAll(profileDir, 0755); err != nil {
+        return err
+    }
+    path := filepath.Join(profileDir, "Preferences")
+    merged := map[string]interface{}{}
+    if data, err := os.ReadFile(path); err == nil {
+        if err := json.Unmarshal(data, &merged); err != nil {
+            return fmt.Errorf("failed to parse %s: %w", path, err)
+        }
+    }
+    mergePrefs(merged, prefs)
+    data, err := json.Marshal(merged)
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(path, data, 0644)
+}
+
+// mergePrefs 将src中的首选项递归合并到dst
+// 如设置"download"下的下载目录时，用户配置中"download"下的其他首选项会被保留
+func mergePrefs(dst, src map[string]interface{}) {
+    for key, value := range src {
+        if sub, ok := value.(map[string]interface{}); ok {
+            if existing, ok := dst[key].(map[string]interface{}); ok {
+                mergePrefs(existing, sub)
+                continue
+            }
+        }
+        dst[key] = value
+    }
+}
+
+// writeFirefoxPrefs 将首选项合并写入Firefox配置目录的user.js
+// 已存在的同名首选项会被替换，其他内容保持不变
+func writeFirefoxPrefs(userDataDir string, prefs map[string]interface{}) error {
+    if err := os.MkdirAll(userDataDir, 0755); err != nil {
+        return err
+    }
+    path := filepath.Join(userDataDir, "user.js")
+    var lines []string
+    if data, err := os.ReadFile(path); err == nil {
+        for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+            if m := firefoxPrefLine.FindStringSubmatch(line); m != nil {
+                if _, replaced := prefs[m[1]]; replaced {
+                    continue
+                }
+            }
+            lines = append(lines, line)
+        }
+    }
+
+    names := make([]string, 0, len(prefs))
+    for name := range prefs {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        value, err := json.Marshal(prefs[name])
+        if err != nil {
+            return fmt.Errorf("invalid firefox pref %s: %w", name, err)
+        }
+        lines = append(lines, fmt.Sprintf("user_pref(%q, %s);", name, value))
+    }
+    return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
@@ -0,0 +1,286 @@
+package webbot
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/url"
+    "os"
+)
+
+// ErrCookieNotFound 表示指定名称的Cookie不存在
+var ErrCookieNotFound = errors.New("webbot: cookie not found")
+
+// Cookie 表示一个浏览器Cookie
+// 字段命名与JSON格式和Playwright的storageState保持一致
+// Expires: 过期时间(Unix时间戳，单位秒)，-1表示会话Cookie
+// SameSite: 可以是"Strict"、"Lax"或"None"
+type Cookie struct {
+    Name     string  `json:"name"`
+    Value    string  `json:"value"`
+    Domain   string  `json:"domain"`
+    Path     string  `json:"path"`
+    Expires  float64 `json:"expires"`
+    HTTPOnly bool    `json:"httpOnly"`
+    Secure   bool    `json:"secure"`
+    SameSite string  `json:"sameSite,omitempty"`
+}
+
+// StorageType 表示Web存储的类型
+// 用于区分localStorage和sessionStorage
+type StorageType string
+
+const (
+    StorageLocal   StorageType = "localStorage"
+    StorageSession StorageType = "sessionStorage"
+)
+
+// StorageItem 表示Web存储中的一个键值对
+type StorageItem struct {
+    Name  string `json:"name"`
+    Value string `json:"value"`
+}
+
+// OriginState 表示某个源(协议+域名+端口)下的Web存储
+// SessionStorage不属于Playwright的标准格式，恢复时按同样的方式写回
+type OriginState struct {
+    Origin         string        `json:"origin"`
+    LocalStorage   []StorageItem `json:"localStorage"`
+    SessionStorage []StorageItem `json:"sessionStorage,omitempty"`
+}
+
+// StorageState 表示一个浏览器的完整登录状态
+// 它可以被保存为JSON文件，并恢复到新的WebBot实例中
+// 文件格式兼容Playwright的storageState
+type StorageState struct {
+    Cookies []Cookie      `json:"cookies"`
+    Origins []OriginState `json:"origins"`
+}
+
+// readStorageState 从JSON文件读取登录状态
+func readStorageState(path string) (StorageState, error) {
+    var state StorageState
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return state, fmt.Errorf("failed to read storage state: %w", err)
+    }
+    if err := json.Unmarshal(data, &state); err != nil {
+        return state, fmt.Errorf("failed to parse storage state %s: %w", path, err)
+    }
+    return state, nil
+}
+
+// writeStorageState 将登录状态写入JSON文件
+func writeStorageState(path string, state StorageState) error {
+    data, err := json.MarshalIndent(state, "", "  ")
+    if err != nil {
+        return fmt.Errorf("failed to encode storage state: %w", err)
+    }
+    if err := os.WriteFile(path, data, 0600); err != nil {
+        return fmt.Errorf("failed to write storage state: %w", err)
+    }
+    return nil
+}
+
+// originOf 返回URL对应的源，如https://example.com:8443
+func originOf(rawURL string) (string, error) {
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        return "", err
+    }
+    if u.Scheme == "" || u.Host == "" {
+        return "", fmt.Errorf("url %q has no origin", rawURL)
+    }
+    return u.Scheme + "://" + u.Host, nil
+}
+
+// 实现WebBot接口的GetCookies方法
+func (b *webBotImpl) GetCookies() ([]Cookie, error) {
+    if err := b.checkPageError(); err != nil {
+        return nil, err
+    }
+    // 实际实现将在后续添加
+    // 这里返回空切片和nil作为占位符
+    return []Cookie{}, nil
+}
+
+// 实现WebBot接口的GetCookie方法
+func (b *webBotImpl) GetCookie(name string) (Cookie, error) {
+    cookies, err := b.GetCookies()
+    if err != nil {
+        return Cookie{}, err
+    }
+    for _, cookie := range cookies {
+        if cookie.Name == name {
+            return cookie, nil
+        }
+    }
+    return Cookie{}, ErrCookieNotFound
+}
+
+// 实现WebBot接口的SetCookie方法
+func (b *webBotImpl) SetCookie(cookie Cookie) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    if cookie.Name == "" {
+        return errors.New("webbot: cookie name is empty")
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的DeleteCookie方法
+func (b *webBotImpl) DeleteCookie(name string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的DeleteAllCookies方法
+func (b *webBotImpl) DeleteAllCookies() error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的GetStorageItems方法
+func (b *webBotImpl) GetStorageItems(storage StorageType) ([]StorageItem, error) {
+    if err := b.checkPageError(); err != nil {
+        return nil, err
+    }
+    // 实际实现将在后续添加
+    // 这里返回空切片和nil作为占位符
+    return []StorageItem{}, nil
+}
+
+// 实现WebBot接口的GetStorageItem方法
+func (b *webBotImpl) GetStorageItem(storage StorageType, key string) (string, error) {
+    if err := b.checkPageError(); err != nil {
+        return "", err
+    }
+    // 实际实现将在后续添加
+    // 这里返回空字符串和nil作为占位符
+    return "", nil
+}
+
+// 实现WebBot接口的SetStorageItem方法
+func (b *webBotImpl) SetStorageItem(storage StorageType, key string, value string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的RemoveStorageItem方法
+func (b *webBotImpl) RemoveStorageItem(storage StorageType, key string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的ClearStorage方法
+func (b *webBotImpl) ClearStorage(storage StorageType) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
+    // 实际实现将在后续添加
+    // 这里返回nil作为占位符
+    return nil
+}
+
+// 实现WebBot接口的StorageState方法
+// Web存储只能按源读取，因此快照中只包含当前页面所在的源
+func (b *webBotImpl) StorageState() (StorageState, error) {
+    state := StorageState{Origins: []OriginState{}}
+
+    cookies, err := b.GetCookies()
+    if err != nil {
+        return state, err
+    }
+    state.Cookies = cookies
+
+    currentURL, err := b.GetURL()
+    if err != nil {
+        return state, err
+    }
+    origin, err := originOf(currentURL)
+    if err != nil {
+        // about:blank等页面没有源，只保存Cookie
+        return state, nil
+    }
+
+    local, err := b.GetStorageItems(StorageLocal)
+    if err != nil {
+        return state, err
+    }
+    session, err := b.GetStorageItems(StorageSession)
+    if err != nil {
+        return state, err
+    }
+    if len(local) > 0 || len(session) > 0 {
+        state.Origins = append(state.Origins, OriginState{
+            Origin:         origin,
+            LocalStorage:   local,
+            SessionStorage: session,
+        })
+    }
+    return state, nil
+}
+
+// 实现WebBot接口的SaveStorageState方法
+func (b *webBotImpl) SaveStorageState(path string) error {
+    state, err := b.StorageState()
+    if err != nil {
+        return err
+    }
+    return writeStorageState(path, state)
+}
+
+// 实现WebBot接口的LoadStorageState方法
+func (b *webBotImpl) LoadStorageState(path string) error {
+    state, err := readStorageState(path)
+    if err != nil {
+        return err
+    }
+    return b.RestoreStorageState(state)
+}
+
+// 实现WebBot接口的RestoreStorageState方法
+func (b *webBotImpl) RestoreStorageState(state StorageState) error {
+    for _, cookie := range state.Cookies {
+        if err := b.SetCookie(cookie); err != nil {
+            return fmt.Errorf("failed to restore cookie %s: %w", cookie.Name, err)
+        }
+    }
+
+    for _, origin := range state.Origins {
+        // Web存储只能在同源页面中写入
+        if err := b.Goto(origin.Origin); err != nil {
+            return fmt.Errorf("failed to navigate to %s: %w", origin.Origin, err)
+        }
+        for _, item := range origin.LocalStorage {
+            if err := b.SetStorageItem(StorageLocal, item.Name, item.Value); err != nil {
+                return err
+            }
+        }
+        for _, item := range origin.SessionStorage {
+            if err := b.SetStorageItem(StorageSession, item.Name, item.Value); err != nil {
+                return err
+            }
+        }
+    }
+    return nil
+}
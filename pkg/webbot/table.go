@@ -0,0 +1,626 @@
+package webbot
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "html"
+    "io"
+    "reflect"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// tableTag 是结构体字段上声明对应表头的标签名
+// 例如：Amount float64 `table:"金额"`，日期字段可以指定格式`table:"日期,layout=2006/01/02"`
+const tableTag = "table"
+
+// maxTableSpan 限制单元格colspan/rowspan的最大值，防止异常页面生成巨大的表格
+const maxTableSpan = 1000
+
+// Table 表示从网页表格中提取的数据
+// Headers: 表头，来自thead或首行全部为th的行
+// Rows: 数据行，colspan和rowspan合并的单元格会在展开后的每个位置重复
+type Table struct {
+    Headers []string
+    Rows    [][]string
+}
+
+// TableOptions 表示提取表格的选项
+// NextPage: "下一页"按钮的定位器，零值表示不翻页
+// MaxPages: 最多提取的页数，0表示直到"下一页"不存在或不可用
+// PageTimeout: 点击"下一页"后等待表格内容变化的最长时间，0表示使用隐式等待时间
+type TableOptions struct {
+    NextPage    By
+    MaxPages    int
+    PageTimeout time.Duration
+}
+
+// ErrColumnNotFound 表示表格中不存在指定的列
+var ErrColumnNotFound = errors.New("webbot: table column not found")
+
+// Column 返回指定表头所在列的所有值
+func (t Table) Column(header string) ([]string, error) {
+    idx := -1
+    for i, h := range t.Headers {
+        if h == header {
+            idx = i
+            break
+        }
+    }
+    if idx < 0 {
+        return nil, fmt.Errorf("%w: %s", ErrColumnNotFound, header)
+    }
+    values := make([]string, len(t.Rows))
+    for i, row := range t.Rows {
+        if idx < len(row) {
+            values[i] = row[idx]
+        }
+    }
+    return values, nil
+}
+
+// Maps 将每一行转换为以表头为键的map
+func (t Table) Maps() []map[string]string {
+    result := make([]map[string]string, 0, len(t.Rows))
+    for _, row := range t.Rows {
+        m := make(map[string]string, len(t.Headers))
+        for i, h := range t.Headers {
+            if i < len(row) {
+                m[h] = row[i]
+            } else {
+                m[h] = ""
+            }
+        }
+        result = append(result, m)
+    }
+    return result
+}
+
+// WriteCSV 将表格以CSV格式写入w，第一行为表头
+func (t Table) WriteCSV(w io.Writer) error {
+    cw := csv.NewWriter(w)
+    if len(t.Headers) > 0 {
+        if err := cw.Write(t.Headers); err != nil {
+            return err
+        }
+    }
+    if err := cw.WriteAll(t.Rows); err != nil {
+        return err
+    }
+    return cw.Error()
+}
+
+// WriteJSON 将表格以JSON对象数组的格式写入w
+// 没有表头时写入二维字符串数组
+func (t Table) WriteJSON(w io.Writer) error {
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    if len(t.Headers) == 0 {
+        return enc.Encode(t.Rows)
+    }
+    return enc.Encode(t.Maps())
+}
+
+// Unmarshal 将表格数据写入结构体切片
+// dst: 结构体切片的指针，字段通过table标签对应表头，没有标签时按字段名(不区分大小写)对应
+// 数字单元格中的千分位逗号和空白会被忽略
+func (t Table) Unmarshal(dst interface{}) error {
+    v := reflect.ValueOf(dst)
+    if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+        return fmt.Errorf("webbot: Table.Unmarshal expects a pointer to a slice, got %T", dst)
+    }
+    slice := v.Elem()
+    elemType := slice.Type().Elem()
+    isPtr := elemType.Kind() == reflect.Pointer
+    if isPtr {
+        elemType = elemType.Elem()
+    }
+    if elemType.Kind() != reflect.Struct {
+        return fmt.Errorf("webbot: Table.Unmarshal expects struct elements, got %s", elemType)
+    }
+
+    type column struct {
+        field  int
+        index  int
+        layout string
+    }
+    var columns []column
+    for i := 0; i < elemType.NumField(); i++ {
+        sf := elemType.Field(i)
+        if !sf.IsExported() {
+            continue
+        }
+        header, layout := sf.Name, "2006-01-02"
+        if tag, ok := sf.Tag.Lookup(tableTag); ok {
+            if tag == "-" {
+                continue
+            }
+            if name, opt, found := strings.Cut(tag, ",layout="); found {
+                tag, layout = name, opt
+            }
+            header = tag
+        }
+        for idx, h := range t.Headers {
+            if h == header || (!hasTag(sf, tableTag) && strings.EqualFold(h, header)) {
+                columns = append(columns, column{field: i, index: idx, layout: layout})
+                break
+            }
+        }
+    }
+
+    result := reflect.MakeSlice(slice.Type(), 0, len(t.Rows))
+    for r, row := range t.Rows {
+        elem := reflect.New(elemType).Elem()
+        for _, c := range columns {
+            if c.index >= len(row) {
+                continue
+            }
+            if err := setCellValue(elem.Field(c.field), row[c.index], c.layout); err != nil {
+                return fmt.Errorf("row %d, column %s: %w", r+1, t.Headers[c.index], err)
+            }
+        }
+        if isPtr {
+            elem = elem.Addr()
+        }
+        result = reflect.Append(result, elem)
+    }
+    slice.Set(result)
+    return nil
+}
+
+// hasTag 判断字段是否声明了指定标签
+func hasTag(sf reflect.StructField, name string) bool {
+    _, ok := sf.Tag.Lookup(name)
+    return ok
+}
+
+// setCellValue 将单元格文本转换后写入字段
+func setCellValue(field reflect.Value, text string, layout string) error {
+    text = strings.TrimSpace(text)
+    if text == "" && field.Kind() == reflect.Pointer {
+        // 空单元格保持指针字段为nil
+        return nil
+    }
+    target := field
+    if target.Kind() == reflect.Pointer {
+        target = reflect.New(field.Type().Elem()).Elem()
+    }
+    switch {
+    case target.Type() == reflect.TypeOf(time.Time{}):
+        if text != "" {
+            t, err := time.Parse(layout, text)
+            if err != nil {
+                return err
+            }
+            target.Set(reflect.ValueOf(t))
+        }
+    case target.Kind() == reflect.String:
+        target.SetString(text)
+    default:
+        // 去掉千分位逗号，如"1,234.50"
+        if err := setFieldValue(target, strings.ReplaceAll(text, ",", ""), ""); err != nil {
+            return err
+        }
+    }
+    if field.Kind() == reflect.Pointer {
+        field.Set(target.Addr())
+    }
+    return nil
+}
+
+// tableCell 表示解析HTML时得到的原始单元格
+type tableCell struct {
+    text    string
+    header  bool
+    colspan int
+    rowspan int
+}
+
+// tableRow 表示解析HTML时得到的原始行
+type tableRow struct {
+    cells   []tableCell
+    section string
+}
+
+var (
+    htmlSpanPattern  = regexp.MustCompile(`^\s*(\d+)`)
+    htmlSpacePattern = regexp.MustCompile(`\s+`)
+)
+
+// htmlTag 表示HTML源码中的一个标签或注释
+type htmlTag struct {
+    start   int
+    end     int
+    comment bool
+    closing bool
+    name    string
+    attrs   string
+}
+
+// nextHTMLTag 从pos开始查找下一个标签或注释
+// 引号中的属性值可以包含">"，不会提前结束标签；找不到完整的标签时返回false
+func nextHTMLTag(source string, pos int) (htmlTag, bool) {
+    for {
+        i := strings.IndexByte(source[pos:], '<')
+        if i < 0 {
+            return htmlTag{}, false
+        }
+        start := pos + i
+        if strings.HasPrefix(source[start:], "<!--") {
+            end := strings.Index(source[start+4:], "-->")
+            if end < 0 {
+                return htmlTag{}, false
+            }
+            return htmlTag{start: start, end: start + 4 + end + 3, comment: true}, true
+        }
+        tag := htmlTag{start: start}
+        j := start + 1
+        if j < len(source) && source[j] == '/' {
+            tag.closing = true
+            j++
+        }
+        k := j
+        for k < len(source) && (isASCIILetter(source[k]) || (k > j && source[k] >= '0' && source[k] <= '9')) {
+            k++
+        }
+        if k == j {
+            // 不是标签，"<"按文本处理
+            pos = start + 1
+            continue
+        }
+        tag.name = strings.ToLower(source[j:k])
+        // 只有紧跟在"="后面的引号才开始一个属性值
+        var quote, prev byte
+        e := k
+        for ; e < len(source); e++ {
+            c := source[e]
+            if quote != 0 {
+                if c == quote {
+                    quote = 0
+                    prev = c
+                }
+                continue
+            }
+            if c == '>' {
+                break
+            }
+            if (c == '"' || c == '\'') && prev == '=' {
+                quote = c
+            }
+            if !isHTMLSpace(c) {
+                prev = c
+            }
+        }
+        if e >= len(source) {
+            return htmlTag{}, false
+        }
+        tag.attrs = source[k:e]
+        tag.end = e + 1
+        return tag, true
+    }
+}
+
+// parseHTMLAttrs 解析标签中的属性，属性名转换为小写
+// 没有值的属性取值为空字符串，重复的属性以第一个为准
+func parseHTMLAttrs(attrs string) map[string]string {
+    result := map[string]string{}
+    i := 0
+    for i < len(attrs) {
+        for i < len(attrs) && (isHTMLSpace(attrs[i]) || attrs[i] == '/') {
+            i++
+        }
+        start := i
+        for i < len(attrs) && !isHTMLSpace(attrs[i]) && attrs[i] != '=' && attrs[i] != '/' {
+            i++
+        }
+        if i == start {
+            i++
+            continue
+        }
+        name := strings.ToLower(attrs[start:i])
+        for i < len(attrs) && isHTMLSpace(attrs[i]) {
+            i++
+        }
+        value := ""
+        if i < len(attrs) && attrs[i] == '=' {
+            i++
+            for i < len(attrs) && isHTMLSpace(attrs[i]) {
+                i++
+            }
+            if i < len(attrs) && (attrs[i] == '"' || attrs[i] == '\'') {
+                quote := attrs[i]
+                end := strings.IndexByte(attrs[i+1:], quote)
+                if end < 0 {
+                    end = len(attrs) - i - 1
+                }
+                value = attrs[i+1 : i+1+end]
+                i += end + 2
+            } else {
+                start := i
+                for i < len(attrs) && !isHTMLSpace(attrs[i]) {
+                    i++
+                }
+                value = attrs[start:i]
+            }
+        }
+        if _, ok := result[name]; !ok {
+            result[name] = html.UnescapeString(value)
+        }
+    }
+    return result
+}
+
+func isASCIILetter(c byte) bool {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isHTMLSpace(c byte) bool {
+    return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// htmlSpan 解析colspan或rowspan属性，无效时返回1，并限制最大值
+func htmlSpan(value string) int {
+    m := htmlSpanPattern.FindStringSubmatch(value)
+    if m == nil {
+        return 1
+    }
+    n, err := strconv.Atoi(m[1])
+    if err != nil || n > maxTableSpan {
+        return maxTableSpan
+    }
+    if n < 1 {
+        return 1
+    }
+    return n
+}
+
+// parseHTMLTable 解析表格元素的outerHTML，返回按行排列的原始单元格
+// 嵌套表格的内容会作为外层单元格的文本
+func parseHTMLTable(source string) []tableRow {
+    var (
+        rows    []tableRow
+        row     *tableRow
+        cell    *tableCell
+        text    strings.Builder
+        depth   int
+        section = "tbody"
+    )
+    flushCell := func() {
+        if cell != nil && row != nil {
+            cell.text = strings.TrimSpace(htmlSpacePattern.ReplaceAllString(html.UnescapeString(text.String()), " "))
+            row.cells = append(row.cells, *cell)
+        }
+        cell = nil
+        text.Reset()
+    }
+    flushRow := func() {
+        flushCell()
+        if row != nil {
+            rows = append(rows, *row)
+        }
+        row = nil
+    }
+
+    last := 0
+    for {
+        tag, ok := nextHTMLTag(source, last)
+        if !ok {
+            break
+        }
+        if cell != nil {
+            text.WriteString(source[last:tag.start])
+        }
+        last = tag.end
+        if tag.comment {
+            continue
+        }
+        closing, name := tag.closing, tag.name
+
+        if name == "table" {
+            if closing {
+                depth--
+            } else {
+                depth++
+            }
+            if depth > 1 || (depth == 1 && closing) {
+                continue
+            }
+        }
+        if depth != 1 {
+            // 嵌套表格中的标签只保留文本
+            if name == "br" && cell != nil {
+                text.WriteString(" ")
+            }
+            continue
+        }
+        switch name {
+        case "thead", "tbody", "tfoot":
+            if !closing {
+                section = name
+            }
+        case "tr":
+            flushRow()
+            if !closing {
+                row = &tableRow{section: section}
+            }
+        case "td", "th":
+            flushCell()
+            if closing {
+                continue
+            }
+            if row == nil {
+                // 省略了<tr>的写法
+                row = &tableRow{section: section}
+            }
+            attrs := parseHTMLAttrs(tag.attrs)
+            cell = &tableCell{
+                header:  name == "th",
+                colspan: htmlSpan(attrs["colspan"]),
+                rowspan: htmlSpan(attrs["rowspan"]),
+            }
+        case "br", "p", "div", "li":
+            if cell != nil {
+                text.WriteString(" ")
+            }
+        }
+    }
+    flushRow()
+    return rows
+}
+
+// expandTable 展开colspan和rowspan，将原始行转换为表头和数据行
+func expandTable(rows []tableRow) Table {
+    // pendingSpans记录被上方单元格rowspan占据的位置：列号 -> 剩余行数和文本
+    type span struct {
+        remaining int
+        text      string
+    }
+    pendingSpans := map[int]*span{}
+    grid := make([][]string, 0, len(rows))
+    for _, r := range rows {
+        var out []string
+        col := 0
+        fill := func() {
+            for {
+                s, ok := pendingSpans[col]
+                if !ok || s.remaining == 0 {
+                    return
+                }
+                out = append(out, s.text)
+                s.remaining--
+                col++
+            }
+        }
+        for _, c := range r.cells {
+            fill()
+            for i := 0; i < c.colspan; i++ {
+                out = append(out, c.text)
+                if c.rowspan > 1 {
+                    pendingSpans[col] = &span{remaining: c.rowspan - 1, text: c.text}
+                }
+                col++
+            }
+        }
+        fill()
+        grid = append(grid, out)
+    }
+
+    var table Table
+    headerRows := 0
+    for i, r := range rows {
+        allHeaders := len(r.cells) > 0
+        for _, c := range r.cells {
+            if !c.header {
+                allHeaders = false
+                break
+            }
+        }
+        if r.section == "thead" || (i == headerRows && allHeaders && r.section != "tfoot") {
+            headerRows = i + 1
+            continue
+        }
+        break
+    }
+    if headerRows > 0 {
+        // 多行表头按列合并，如"销售额 / 一季度"
+        width := 0
+        for _, r := range grid[:headerRows] {
+            if len(r) > width {
+                width = len(r)
+            }
+        }
+        table.Headers = make([]string, width)
+        for c := 0; c < width; c++ {
+            var parts []string
+            for _, r := range grid[:headerRows] {
+                if c < len(r) && r[c] != "" && (len(parts) == 0 || parts[len(parts)-1] != r[c]) {
+                    parts = append(parts, r[c])
+                }
+            }
+            table.Headers[c] = strings.Join(parts, " / ")
+        }
+    }
+    table.Rows = grid[headerRows:]
+    return table
+}
+
+// isNextPageAvailable 判断"下一页"按钮是否存在且可用
+func (b *webBotImpl) isNextPageAvailable(by By) (bool, error) {
+    elements, err := b.FindElements(by)
+    if err != nil || len(elements) == 0 {
+        return false, err
+    }
+    // disabled是布尔属性，只要存在就表示禁用，包括disabled=""和disabled="false"
+    disabled, err := b.hasElementAttribute(by, "disabled")
+    if err != nil || disabled {
+        return false, err
+    }
+    ariaDisabled, err := b.GetElementAttribute(by, "aria-disabled")
+    if err != nil {
+        return false, err
+    }
+    if ariaDisabled != "" && ariaDisabled != "false" {
+        return false, nil
+    }
+    class, err := b.GetElementAttribute(by, "class")
+    if err != nil {
+        return false, err
+    }
+    for _, c := range strings.Fields(class) {
+        if c == "disabled" {
+            return false, nil
+        }
+    }
+    return true, nil
+}
+
+// 实现WebBot接口的ExtractTable方法
+func (b *webBotImpl) ExtractTable(by By, options TableOptions) (Table, error) {
+    if err := b.checkPageError(); err != nil {
+        return Table{}, err
+    }
+    source, err := b.GetElementAttribute(by, "outerHTML")
+    if err != nil {
+        return Table{}, err
+    }
+    table := expandTable(parseHTMLTable(source))
+    if options.NextPage.IsZero() {
+        return table, nil
+    }
+
+    timeout := options.PageTimeout
+    if timeout == 0 {
+        timeout = time.Duration(b.implicitWait * float64(time.Second))
+    }
+    for page := 1; options.MaxPages == 0 || page < options.MaxPages; page++ {
+        available, err := b.isNextPageAvailable(options.NextPage)
+        if err != nil {
+            return table, err
+        }
+        if !available {
+            break
+        }
+        if err := b.ClickElement(options.NextPage); err != nil {
+            return table, err
+        }
+        // 等待表格内容变化，内容不变说明已经是最后一页
+        next := source
+        deadline := time.Now().Add(timeout)
+        for next == source && time.Now().Before(deadline) {
+            time.Sleep(100 * time.Millisecond)
+            if next, err = b.GetElementAttribute(by, "outerHTML"); err != nil {
+                return table, err
+            }
+        }
+        if next == source {
+            break
+        }
+        source = next
+        table.Rows = append(table.Rows, expandTable(parseHTMLTable(source)).Rows...)
+    }
+    return table, nil
+}
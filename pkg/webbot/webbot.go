@@ -1,13 +1,22 @@
 // Package webbot 提供Web平台自动化的功能和接口
 package webbot
 
-import "github.com/zhangsan-ai/go-aibote/pkg/common"
+import (
+    "encoding/json"
+    "errors"
+    "image"
+    "sync"
+    "time"
+
+    "github.com/zhangsan-ai/go-aibote/pkg/common"
+)
 
 // WebElement 表示一个网页元素
 // ID: 元素ID
 // XPath: 元素的XPath路径
 // CSSSelector: 元素的CSS选择器
 // TagName: 元素的标签名
+// Locator: 查找该元素时使用的定位器，可以通过Locator.Find在元素内部继续查找
 // 这个结构体包含了网页元素的基本信息
 // 在Web自动化中，经常需要根据这些信息来定位和操作特定的元素
 // 这些属性提供了多种定位元素的方式，以适应不同的场景
@@ -17,6 +26,7 @@ type WebElement struct {
     XPath       string
     CSSSelector string
     TagName     string
+    Locator     By
 }
 
 // WebBot 接口定义了Web平台自动化的方法
@@ -36,18 +46,19 @@ type WebBot interface {
     Goto(url string) error
     
     // FindElement 查找单个网页元素
-    // selector: 元素选择器，可以是XPath、CSS选择器等
+    // by: 元素定位器，如webbot.CSS("#login")、webbot.XPath("//input")
+    // 链式定位器会在父元素内部查找，如webbot.ID("form").Find(webbot.Name("user"))
     // 返回WebElement结构体和error类型
     // 如果查找成功，则返回元素和nil
     // 否则返回空WebElement和具体的错误信息
-    FindElement(selector string) (WebElement, error)
+    FindElement(by By) (WebElement, error)
     
     // FindElements 查找多个网页元素
-    // selector: 元素选择器，可以是XPath、CSS选择器等
+    // by: 元素定位器，用法与FindElement相同
     // 返回WebElement结构体列表和error类型
     // 如果查找成功，则返回元素列表和nil
     // 否则返回空列表和具体的错误信息
-    FindElements(selector string) ([]WebElement, error)
+    FindElements(by By) ([]WebElement, error)
     
     // GetTitle 获取当前网页的标题
     // 返回标题字符串和error类型
@@ -92,7 +103,294 @@ type WebBot interface {
     // 如果获取成功，则返回参数值和nil
     // 否则返回空字符串和具体的错误信息
     GetExtendParam() (string, error)
-    
+
+    // GetCookies 获取当前页面可见的所有Cookie
+    // 返回Cookie列表和error类型
+    GetCookies() ([]Cookie, error)
+
+    // GetCookie 获取指定名称的Cookie
+    // name: Cookie名称
+    // 如果Cookie不存在，则返回ErrCookieNotFound
+    GetCookie(name string) (Cookie, error)
+
+    // SetCookie 设置一个Cookie
+    // cookie: 要设置的Cookie，Domain为空时使用当前页面的域名
+    // 返回error类型，如果设置成功则返回nil，否则返回具体的错误信息
+    SetCookie(cookie Cookie) error
+
+    // DeleteCookie 删除指定名称的Cookie
+    // name: Cookie名称
+    // 返回error类型，如果删除成功则返回nil，否则返回具体的错误信息
+    DeleteCookie(name string) error
+
+    // DeleteAllCookies 删除当前浏览器上下文中的所有Cookie
+    // 返回error类型，如果删除成功则返回nil，否则返回具体的错误信息
+    DeleteAllCookies() error
+
+    // GetStorageItems 获取当前页面的全部Web存储项
+    // storage: 存储类型，localStorage或sessionStorage
+    // 返回存储项列表和error类型
+    GetStorageItems(storage StorageType) ([]StorageItem, error)
+
+    // GetStorageItem 获取Web存储中指定键的值
+    // storage: 存储类型，localStorage或sessionStorage
+    // key: 存储键名
+    // 返回存储值和error类型
+    GetStorageItem(storage StorageType, key string) (string, error)
+
+    // SetStorageItem 设置Web存储中指定键的值
+    // storage: 存储类型，localStorage或sessionStorage
+    // key: 存储键名
+    // value: 存储值
+    // 返回error类型，如果设置成功则返回nil，否则返回具体的错误信息
+    SetStorageItem(storage StorageType, key string, value string) error
+
+    // RemoveStorageItem 删除Web存储中指定键
+    // storage: 存储类型，localStorage或sessionStorage
+    // key: 存储键名
+    // 返回error类型，如果删除成功则返回nil，否则返回具体的错误信息
+    RemoveStorageItem(storage StorageType, key string) error
+
+    // ClearStorage 清空当前页面的Web存储
+    // storage: 存储类型，localStorage或sessionStorage
+    // 返回error类型，如果清空成功则返回nil，否则返回具体的错误信息
+    ClearStorage(storage StorageType) error
+
+    // StorageState 获取当前的登录状态快照
+    // 包括所有Cookie以及当前页面源的localStorage和sessionStorage
+    // 返回StorageState结构体和error类型
+    StorageState() (StorageState, error)
+
+    // SaveStorageState 将当前的登录状态快照保存为JSON文件
+    // path: 脚本所在主机上的文件路径
+    // 返回error类型，如果保存成功则返回nil，否则返回具体的错误信息
+    SaveStorageState(path string) error
+
+    // LoadStorageState 从JSON文件恢复登录状态
+    // path: 由SaveStorageState生成的文件路径
+    // 返回error类型，如果恢复成功则返回nil，否则返回具体的错误信息
+    LoadStorageState(path string) error
+
+    // RestoreStorageState 将登录状态快照恢复到当前浏览器
+    // state: 要恢复的登录状态
+    // 恢复Web存储时会依次导航到每个源
+    // 返回error类型，如果恢复成功则返回nil，否则返回具体的错误信息
+    RestoreStorageState(state StorageState) error
+
+    // Screenshot 截取当前页面
+    // fullPage: true表示截取整个可滚动页面，false表示只截取当前视口
+    // 返回PNG格式的图片数据和error类型
+    // 图片数据通过驱动连接返回，不会写入驱动所在主机的文件
+    Screenshot(fullPage bool) ([]byte, error)
+
+    // ScreenshotImage 截取当前页面并解码为image.Image
+    // fullPage: true表示截取整个可滚动页面，false表示只截取当前视口
+    ScreenshotImage(fullPage bool) (image.Image, error)
+
+    // ElementScreenshot 截取单个元素
+    // by: 元素定位器
+    // 返回PNG格式的图片数据和error类型
+    ElementScreenshot(by By) ([]byte, error)
+
+    // ElementScreenshotImage 截取单个元素并解码为image.Image
+    // by: 元素定位器
+    ElementScreenshotImage(by By) (image.Image, error)
+
+    // PrintToPDF 将当前页面打印为PDF
+    // options: 纸张尺寸、页边距、横向等打印选项
+    // 返回PDF数据和error类型
+    PrintToPDF(options PDFOptions) ([]byte, error)
+
+    // GetDialog 获取当前打开的JavaScript弹窗
+    // 返回Dialog结构体和error类型
+    // 如果没有打开的弹窗，则返回ErrNoDialog
+    GetDialog() (Dialog, error)
+
+    // IsDialogOpen 判断当前页面是否有打开的JavaScript弹窗
+    // 返回布尔值和error类型
+    IsDialogOpen() (bool, error)
+
+    // AcceptDialog 接受(点击确定)当前弹窗
+    // 返回error类型，如果没有打开的弹窗则返回ErrNoDialog
+    AcceptDialog() error
+
+    // DismissDialog 取消当前弹窗
+    // 返回error类型，如果没有打开的弹窗则返回ErrNoDialog
+    DismissDialog() error
+
+    // AcceptPrompt 在prompt弹窗中输入文本并接受
+    // text: 要输入的文本
+    // 返回error类型，如果当前弹窗不是prompt则返回错误
+    AcceptPrompt(text string) error
+
+    // SetDialogHandler 设置弹窗处理策略
+    // handler: 弹窗处理函数，nil表示不自动处理弹窗
+    // 可以使用AcceptDialogs、DismissDialogs或自定义函数
+    SetDialogHandler(handler DialogHandler)
+
+    // UploadFile 向<input type=file>元素上传文件
+    // by: 文件输入框的定位器
+    // paths: 脚本所在主机上的文件路径，多个路径要求输入框带有multiple属性
+    // 返回error类型，如果上传成功则返回nil，否则返回具体的错误信息
+    UploadFile(by By, paths ...string) error
+
+    // DownloadDir 返回当前的下载目录
+    // 未设置WithDownloadDir时返回空字符串
+    DownloadDir() string
+
+    // WaitForDownload 执行trigger并等待一个新的下载完成
+    // trigger: 触发下载的操作，如点击导出按钮，可以为nil
+    // timeout: 最长等待时间
+    // 返回下载文件的信息和error类型
+    // 超时返回*DownloadTimeoutError
+    WaitForDownload(trigger func() error, timeout time.Duration) (Download, error)
+
+    // Route 注册一个请求拦截路由
+    // pattern: URL通配符模式，如"**/api/*"，以"re:"开头表示正则表达式
+    // handler: 路由处理函数，可以继续、修改请求头、使用固定内容响应或中止请求
+    // 多个路由匹配同一请求时，后注册的路由优先
+    // 返回error类型，如果注册成功则返回nil，否则返回具体的错误信息
+    Route(pattern string, handler RouteHandler) error
+
+    // Unroute 移除指定模式的所有路由
+    // pattern: 注册时使用的URL模式
+    // 返回error类型，如果移除成功则返回nil，否则返回具体的错误信息
+    Unroute(pattern string) error
+
+    // SubscribeNetwork 订阅页面的请求和响应事件
+    // 返回事件通道和取消订阅函数
+    // 处理过慢时超出缓冲的事件会被丢弃
+    SubscribeNetwork() (<-chan NetworkEvent, func())
+
+    // WaitForResponse 执行trigger并等待一个匹配的响应
+    // match: 响应匹配函数，nil表示匹配任意响应
+    // trigger: 触发请求的操作，可以为nil
+    // timeout: 最长等待时间，超时返回*ResponseTimeoutError
+    WaitForResponse(match func(Response) bool, trigger func() error, timeout time.Duration) (Response, error)
+
+    // SaveHAR 将目前已录制的请求和响应写入HAR文件
+    // path: HAR文件保存路径
+    // 需要通过WithHAR开启录制，StopServer时也会自动写入WithHAR指定的文件
+    SaveHAR(path string) error
+
+    // DebugPort 返回浏览器实际使用的调试端口
+    // WithDebugPort为0时，启动后返回随机分配的端口
+    DebugPort() int
+
+    // BrowserStderr 返回浏览器进程最近的stderr输出，用于诊断启动或崩溃问题
+    // 接管已打开的浏览器时返回空字符串
+    BrowserStderr() string
+
+    // CDPSend 通过调试端口直接发送Chrome DevTools Protocol命令
+    // method: CDP方法名，如"Performance.getMetrics"、"Page.addScriptToEvaluateOnNewDocument"
+    // params: 命令参数，会被编码为JSON，可以为nil
+    // 返回命令结果的原始JSON和error类型，浏览器返回的错误为*CDPError
+    // 仅支持Chrome和Edge
+    CDPSend(method string, params interface{}) (json.RawMessage, error)
+
+    // SubscribeCDP 订阅CDP事件
+    // method: 事件名称，如"Network.requestWillBeSent"，为空表示订阅所有事件
+    // 返回事件通道、取消订阅函数和error类型
+    // 大多数事件需要先通过CDPSend开启对应的域，如"Network.enable"
+    SubscribeCDP(method string) (<-chan CDPEvent, func(), error)
+
+    // ClickElement 点击元素
+    // by: 元素定位器
+    // 返回error类型，如果点击成功则返回nil，否则返回具体的错误信息
+    ClickElement(by By) error
+
+    // InputText 向元素输入文本，文本追加在已有内容之后
+    // by: 元素定位器
+    // text: 要输入的文本
+    // 返回error类型，如果输入成功则返回nil，否则返回具体的错误信息
+    InputText(by By, text string) error
+
+    // ClearElement 清空输入框的内容
+    // by: 元素定位器
+    // 返回error类型，如果清空成功则返回nil，否则返回具体的错误信息
+    ClearElement(by By) error
+
+    // GetElementText 获取元素的可见文本
+    // by: 元素定位器
+    // 返回文本字符串和error类型
+    GetElementText(by By) (string, error)
+
+    // GetElementAttribute 获取元素的属性值
+    // by: 元素定位器
+    // name: 属性名称，如"href"、"type"
+    // 返回属性值和error类型，属性不存在时返回空字符串
+    GetElementAttribute(by By, name string) (string, error)
+
+    // GetElementValue 获取表单控件的当前值(value属性)
+    // by: 元素定位器
+    // 返回值字符串和error类型
+    GetElementValue(by By) (string, error)
+
+    // SetElementValue 直接设置表单控件的值，并触发input和change事件
+    // by: 元素定位器
+    // value: 要设置的值
+    // 适用于日期选择器等无法可靠地通过键盘输入的控件
+    SetElementValue(by By, value string) error
+
+    // SelectOption 在下拉框中选择选项
+    // by: select元素的定位器
+    // option: 选项的value或可见文本
+    // 返回error类型，如果选择成功则返回nil，否则返回具体的错误信息
+    SelectOption(by By, option string) error
+
+    // IsElementSelected 判断复选框、单选框或选项是否被选中
+    // by: 元素定位器
+    // 返回布尔值和error类型
+    IsElementSelected(by By) (bool, error)
+
+    // FillForm 一次填写整个表单
+    // values: map[string]T或带aibote标签的结构体(或其指针)
+    // map的键和结构体标签都是定位器文本，如"css=#email"，不带策略时按name属性定位
+    // 根据控件类型自动处理文本框、下拉框、复选框、单选框和日期选择器
+    // 单个字段失败不会中断其他字段，所有失败的字段通过*FormError返回
+    FillForm(values interface{}) error
+
+    // ReadForm 读取表单当前的值并写入结构体
+    // dst: 带aibote标签的结构体指针
+    // 所有失败的字段通过*FormError返回
+    ReadForm(dst interface{}) error
+
+    // ExtractTable 提取网页表格的表头和数据行
+    // by: table元素的定位器
+    // options: 翻页选项，设置NextPage后会不断点击"下一页"直到按钮不存在、不可用或达到MaxPages
+    // colspan和rowspan合并的单元格会展开到每个位置
+    // 返回Table结构体和error类型，可以通过Table.WriteCSV、WriteJSON、Unmarshal导出
+    ExtractTable(by By, options TableOptions) (Table, error)
+
+    // SubscribeConsole 订阅页面输出的控制台消息
+    // 返回消息通道和取消订阅函数
+    // 处理过慢时超出缓冲的消息会被丢弃，但仍会保存在DrainConsole的缓冲区中
+    SubscribeConsole() (<-chan ConsoleMessage, func())
+
+    // SubscribePageErrors 订阅页面中未被捕获的JavaScript异常
+    // 返回错误通道和取消订阅函数
+    SubscribePageErrors() (<-chan PageError, func())
+
+    // DrainConsole 返回并清空缓冲区中的控制台消息，按时间先后排列
+    DrainConsole() []ConsoleMessage
+
+    // DrainPageErrors 返回并清空缓冲区中的页面错误，按时间先后排列
+    DrainPageErrors() []PageError
+
+    // Actions 创建一个鼠标、键盘和滚轮动作构建器
+    // 构建完成后调用Perform一次性执行，例如：
+    // bot.Actions().DragAndDrop(webbot.CSS("#card"), webbot.CSS("#done")).Perform()
+    Actions() *Actions
+
+    // PerformActions 一次性执行一个动作序列
+    // 执行期间其他动作序列不会插入
+    // 返回error类型，如果执行成功则返回nil，否则返回具体的错误信息
+    PerformActions(actions *Actions) error
+
+    // ReleaseActions 释放所有仍处于按下状态的按键和鼠标按键
+    // 动作序列执行失败后调用，避免修饰键保持按下影响后续操作
+    ReleaseActions() error
+
     // 其他Web特定方法将在后续实现
 }
 
@@ -141,6 +439,7 @@ func WithDebugPort(port int) WebBotOption {
 // WithUserDataDir 设置用户数据目录
 // dir: 用户数据目录路径
 // 多进程同时操作多个浏览器时，数据目录不能相同
+// 为空表示使用临时目录，StopServer时会自动删除
 // 返回WebBotOption类型的函数
 // 这个函数将在创建WebBot实例时应用用户数据目录配置
 func WithUserDataDir(dir string) WebBotOption {
@@ -163,7 +462,9 @@ func WithBrowserPath(path string) WebBotOption {
 
 // WithArguments 设置浏览器启动参数
 // args: 浏览器启动参数列表
-// 例如：--headless(无头模式)、--proxy-server(代理设置)等
+// 例如：--disable-gpu、--mute-audio等
+// 无头模式、代理、User-Agent、视口等常用设置请使用WithHeadless、WithProxy等选项
+// 它们会根据浏览器类型转换为正确的参数，这里的参数追加在其后
 // 返回WebBotOption类型的函数
 // 这个函数将在创建WebBot实例时应用浏览器启动参数配置
 func WithArguments(args []string) WebBotOption {
@@ -205,6 +506,17 @@ func WithImplicitWaitFrequency(frequency float64) WebBotOption {
     }
 }
 
+// WithStorageState 使用已保存的登录状态启动WebBot
+// path: 由SaveStorageState生成的JSON文件路径
+// 文件在创建WebBot实例时读取，脚本执行前恢复到浏览器中
+// 多个并行的WebBot可以共享同一个文件，从而避免重复登录
+// 返回WebBotOption类型的函数
+func WithStorageState(path string) WebBotOption {
+    return func(b *webBotImpl) {
+        b.storageStatePath = path
+    }
+}
+
 // NewWebBot 创建一个新的WebBot实例
 // options: 可变参数，包含WebBot的配置选项
 // 返回WebBot接口和error类型
@@ -224,6 +536,8 @@ func NewWebBot(options ...WebBotOption) (WebBot, error) {
         extendParam:          "",            // 默认无扩展参数
         implicitWait:         5.0,           // 默认隐式等待5秒
         implicitWaitFrequency: 0.5,          // 默认每0.5秒重试一次
+        launchTimeout:        30 * time.Second, // 默认等待浏览器启动30秒
+        console:              consoleBuffer{size: defaultConsoleBufferSize}, // 默认保留1000条控制台记录
     }
     
     // 应用所有选项
@@ -232,7 +546,23 @@ func NewWebBot(options ...WebBotOption) (WebBot, error) {
     }
     
     // 初始化其他必要的组件
-    // ...
+    if bot.storageStatePath != "" {
+        state, err := readStorageState(bot.storageStatePath)
+        if err != nil {
+            return nil, err
+        }
+        bot.initialState = &state
+    }
+    if bot.harPath != "" {
+        if err := bot.startHARRecording(); err != nil {
+            return nil, err
+        }
+    }
+    if bot.harReplayPath != "" {
+        if err := bot.startHARReplay(); err != nil {
+            return nil, err
+        }
+    }
     
     return bot, nil
 }
@@ -248,45 +578,135 @@ type webBotImpl struct {
     extendParam          string
     implicitWait         float64
     implicitWaitFrequency float64
+    storageStatePath     string
+    initialState         *StorageState
+    dialogHandler        DialogHandler
+    downloadDir          string
+    routes               []route
+    networkEvents        broadcaster[NetworkEvent]
+    harPath              string
+    harOptions           HAROptions
+    harRecorder          *harRecorder
+    harReplayPath        string
+    harNotFound          HARNotFound
+    emulation            emulation
+    launchTimeout        time.Duration
+    browser              *browserProcess
+    tempProfileDir       string
+    cdp                  *cdpClient
+    console              consoleBuffer
+    consoleEvents        broadcaster[ConsoleMessage]
+    pageErrorEvents      broadcaster[PageError]
+    failOnPageError      bool
+    scriptRunning        bool
+    scriptPageError      *PageError
+    actionsMu            sync.Mutex
+    mu                   sync.Mutex
     // 其他必要的字段将在后续实现中添加
 }
 
 // 实现common.Bot接口的StartServer方法
-func (b *webBotImpl) StartServer(ip string, port int) error {
-    // 实际实现将在后续添加
-    // 这里返回nil作为占位符
-    return nil
+func (b *webBotImpl) StartServer(ip string, port int) (err error) {
+    if b.browser != nil && !b.browser.exited() {
+        return errors.New("webbot: browser is already running, call StopServer first")
+    }
+    // 任何一步失败都结束已启动的浏览器并删除临时用户数据目录
+    defer func() {
+        if err != nil {
+            b.stopBrowser()
+        }
+    }()
+
+    // WithDebugPort指定的端口已经在监听时直接接管已打开的浏览器
+    // 该浏览器不是WebBot启动的，不修改它的用户数据目录
+    attached := b.debugPort != 0 && portReady(b.debugPort)
+    if !attached {
+        // 浏览器启动前准备用户数据目录，写入下载目录和模拟设置
+        if err := b.prepareProfileDir(); err != nil {
+            return err
+        }
+    }
+    if err := b.prepareDownloadDir(!attached); err != nil {
+        return err
+    }
+    if !attached {
+        if err := b.prepareEmulation(); err != nil {
+            return err
+        }
+        cfg, err := b.browserLaunchConfig()
+        if err != nil {
+            return err
+        }
+        if err := b.launchBrowser(cfg); err != nil {
+            return err
+        }
+    }
+    // 连接驱动的实际实现将在后续添加
+    return b.applyEmulation()
 }
 
 // 实现common.Bot接口的StopServer方法
 func (b *webBotImpl) StopServer() error {
-    // 实际实现将在后续添加
-    // 这里返回nil作为占位符
-    return nil
+    b.networkEvents.closeAll()
+    b.consoleEvents.closeAll()
+    b.pageErrorEvents.closeAll()
+    harErr := b.stopHARRecording()
+    b.closeCDP()
+    // 断开驱动连接的实际实现将在后续添加
+    if err := b.stopBrowser(); err != nil {
+        return err
+    }
+    return harErr
 }
 
 // 实现common.Bot接口的ExecuteScript方法
-func (b *webBotImpl) ExecuteScript(script func(bot common.Bot) error) error {
-    // 直接调用传入的脚本函数
+func (b *webBotImpl) ExecuteScript(script func(bot common.Bot) error) (err error) {
+    // 先恢复WithStorageState指定的登录状态
+    if b.initialState != nil {
+        if err := b.RestoreStorageState(*b.initialState); err != nil {
+            return err
+        }
+    }
+    // 调用传入的脚本函数，并记录执行期间的页面错误
+    // 脚本panic时也要结束记录，否则之后的调用会一直返回旧的页面错误
+    b.beginScript()
+    defer func() {
+        err = b.endScript(err)
+    }()
     return script(b)
 }
 
 // 实现WebBot接口的Goto方法
 func (b *webBotImpl) Goto(url string) error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
     // 实际实现将在后续添加
     // 这里返回nil作为占位符
     return nil
 }
 
 // 实现WebBot接口的FindElement方法
-func (b *webBotImpl) FindElement(selector string) (WebElement, error) {
+func (b *webBotImpl) FindElement(by By) (WebElement, error) {
+    if err := b.checkPageError(); err != nil {
+        return WebElement{}, err
+    }
+    if err := by.validate(); err != nil {
+        return WebElement{}, err
+    }
     // 实际实现将在后续添加
-    // 这里返回空WebElement和nil作为占位符
-    return WebElement{}, nil
+    // 这里返回只包含定位器的WebElement和nil作为占位符
+    return WebElement{Locator: by}, nil
 }
 
 // 实现WebBot接口的FindElements方法
-func (b *webBotImpl) FindElements(selector string) ([]WebElement, error) {
+func (b *webBotImpl) FindElements(by By) ([]WebElement, error) {
+    if err := b.checkPageError(); err != nil {
+        return nil, err
+    }
+    if err := by.validate(); err != nil {
+        return nil, err
+    }
     // 实际实现将在后续添加
     // 这里返回空切片和nil作为占位符
     return []WebElement{}, nil
@@ -294,6 +714,9 @@ func (b *webBotImpl) FindElements(selector string) ([]WebElement, error) {
 
 // 实现WebBot接口的GetTitle方法
 func (b *webBotImpl) GetTitle() (string, error) {
+    if err := b.checkPageError(); err != nil {
+        return "", err
+    }
     // 实际实现将在后续添加
     // 这里返回空字符串和nil作为占位符
     return "", nil
@@ -301,6 +724,9 @@ func (b *webBotImpl) GetTitle() (string, error) {
 
 // 实现WebBot接口的GetURL方法
 func (b *webBotImpl) GetURL() (string, error) {
+    if err := b.checkPageError(); err != nil {
+        return "", err
+    }
     // 实际实现将在后续添加
     // 这里返回空字符串和nil作为占位符
     return "", nil
@@ -308,6 +734,9 @@ func (b *webBotImpl) GetURL() (string, error) {
 
 // 实现WebBot接口的Refresh方法
 func (b *webBotImpl) Refresh() error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
     // 实际实现将在后续添加
     // 这里返回nil作为占位符
     return nil
@@ -315,6 +744,9 @@ func (b *webBotImpl) Refresh() error {
 
 // 实现WebBot接口的Back方法
 func (b *webBotImpl) Back() error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
     // 实际实现将在后续添加
     // 这里返回nil作为占位符
     return nil
@@ -322,6 +754,9 @@ func (b *webBotImpl) Back() error {
 
 // 实现WebBot接口的Forward方法
 func (b *webBotImpl) Forward() error {
+    if err := b.checkPageError(); err != nil {
+        return err
+    }
     // 实际实现将在后续添加
     // 这里返回nil作为占位符
     return nil
@@ -0,0 +1,219 @@
+package webbot
+
+import (
+    "bufio"
+    "crypto/rand"
+    "crypto/sha1"
+    "encoding/base64"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "net/url"
+    "sync"
+    "time"
+)
+
+// websocketGUID 是RFC 6455中用于计算Sec-WebSocket-Accept的固定GUID
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket帧操作码
+const (
+    wsOpContinuation = 0x0
+    wsOpText         = 0x1
+    wsOpBinary       = 0x2
+    wsOpClose        = 0x8
+    wsOpPing         = 0x9
+    wsOpPong         = 0xA
+)
+
+// wsMaxMessageSize 是允许接收的最大消息大小，防止异常数据耗尽内存
+const wsMaxMessageSize = 256 << 20
+
+// wsConn 是一个最小化的WebSocket客户端连接
+// 只实现与DevTools调试端口通信所需的功能：文本消息、分片、ping/pong和关闭
+type wsConn struct {
+    conn    net.Conn
+    br      *bufio.Reader
+    writeMu sync.Mutex
+}
+
+// dialWebSocket 连接到ws://地址并完成握手
+func dialWebSocket(rawURL string, timeout time.Duration) (*wsConn, error) {
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        return nil, err
+    }
+    if u.Scheme != "ws" {
+        return nil, fmt.Errorf("unsupported websocket scheme %q", u.Scheme)
+    }
+    host := u.Host
+    if u.Port() == "" {
+        host = net.JoinHostPort(u.Hostname(), "80")
+    }
+    conn, err := net.DialTimeout("tcp", host, timeout)
+    if err != nil {
+        return nil, err
+    }
+
+    keyBytes := make([]byte, 16)
+    if _, err := rand.Read(keyBytes); err != nil {
+        conn.Close()
+        return nil, err
+    }
+    key := base64.StdEncoding.EncodeToString(keyBytes)
+    path := u.RequestURI()
+    request := "GET " + path + " HTTP/1.1\r\n" +
+        "Host: " + u.Host + "\r\n" +
+        "Upgrade: websocket\r\n" +
+        "Connection: Upgrade\r\n" +
+        "Sec-WebSocket-Key: " + key + "\r\n" +
+        "Sec-WebSocket-Version: 13\r\n\r\n"
+
+    conn.SetDeadline(time.Now().Add(timeout))
+    if _, err := io.WriteString(conn, request); err != nil {
+        conn.Close()
+        return nil, err
+    }
+    br := bufio.NewReader(conn)
+    resp, err := http.ReadResponse(br, &http.Request{Method: http.MethodGet})
+    if err != nil {
+        conn.Close()
+        return nil, fmt.Errorf("websocket handshake failed: %w", err)
+    }
+    resp.Body.Close()
+    if resp.StatusCode != http.StatusSwitchingProtocols {
+        conn.Close()
+        return nil, fmt.Errorf("websocket handshake failed: unexpected status %s", resp.Status)
+    }
+    sum := sha1.Sum([]byte(key + websocketGUID))
+    if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+        conn.Close()
+        return nil, errors.New("websocket handshake failed: invalid Sec-WebSocket-Accept")
+    }
+    conn.SetDeadline(time.Time{})
+    return &wsConn{conn: conn, br: br}, nil
+}
+
+// writeFrame 发送一个带掩码的完整帧，客户端发送的帧必须带掩码
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+    header := []byte{0x80 | opcode}
+    switch n := len(payload); {
+    case n < 126:
+        header = append(header, 0x80|byte(n))
+    case n <= 0xFFFF:
+        header = append(header, 0x80|126, 0, 0)
+        binary.BigEndian.PutUint16(header[2:], uint16(n))
+    default:
+        header = append(header, 0x80|127, 0, 0, 0, 0, 0, 0, 0, 0)
+        binary.BigEndian.PutUint64(header[2:], uint64(n))
+    }
+    mask := make([]byte, 4)
+    if _, err := rand.Read(mask); err != nil {
+        return err
+    }
+    header = append(header, mask...)
+    masked := make([]byte, len(payload))
+    for i, v := range payload {
+        masked[i] = v ^ mask[i%4]
+    }
+
+    c.writeMu.Lock()
+    defer c.writeMu.Unlock()
+    if _, err := c.conn.Write(append(header, masked...)); err != nil {
+        return err
+    }
+    return nil
+}
+
+// writeText 发送一条文本消息
+func (c *wsConn) writeText(data []byte) error {
+    return c.writeFrame(wsOpText, data)
+}
+
+// readMessage 读取下一条完整的文本或二进制消息
+// ping会被自动回复，收到关闭帧时返回io.EOF
+func (c *wsConn) readMessage() ([]byte, error) {
+    var message []byte
+    for {
+        fin, opcode, payload, err := c.readFrame()
+        if err != nil {
+            return nil, err
+        }
+        switch opcode {
+        case wsOpPing:
+            if err := c.writeFrame(wsOpPong, payload); err != nil {
+                return nil, err
+            }
+            continue
+        case wsOpPong:
+            continue
+        case wsOpClose:
+            c.writeFrame(wsOpClose, nil)
+            return nil, io.EOF
+        case wsOpText, wsOpBinary, wsOpContinuation:
+            message = append(message, payload...)
+            if len(message) > wsMaxMessageSize {
+                return nil, errors.New("websocket message too large")
+            }
+            if fin {
+                return message, nil
+            }
+        default:
+            return nil, fmt.Errorf("unsupported websocket opcode %d", opcode)
+        }
+    }
+}
+
+// readFrame 读取一个帧
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+    var head [2]byte
+    if _, err := io.ReadFull(c.br, head[:]); err != nil {
+        return false, 0, nil, err
+    }
+    fin := head[0]&0x80 != 0
+    opcode := head[0] & 0x0F
+    masked := head[1]&0x80 != 0
+    length := uint64(head[1] & 0x7F)
+    switch length {
+    case 126:
+        var ext [2]byte
+        if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+            return false, 0, nil, err
+        }
+        length = uint64(binary.BigEndian.Uint16(ext[:]))
+    case 127:
+        var ext [8]byte
+        if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+            return false, 0, nil, err
+        }
+        length = binary.BigEndian.Uint64(ext[:])
+    }
+    if length > wsMaxMessageSize {
+        return false, 0, nil, errors.New("websocket frame too large")
+    }
+    var mask [4]byte
+    if masked {
+        if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+            return false, 0, nil, err
+        }
+    }
+    payload := make([]byte, length)
+    if _, err := io.ReadFull(c.br, payload); err != nil {
+        return false, 0, nil, err
+    }
+    if masked {
+        for i := range payload {
+            payload[i] ^= mask[i%4]
+        }
+    }
+    return fin, opcode, payload, nil
+}
+
+// close 发送关闭帧并关闭底层连接
+func (c *wsConn) close() error {
+    c.writeFrame(wsOpClose, nil)
+    return c.conn.Close()
+}
@@ -0,0 +1,319 @@
+package webbot
+
+import (
+    "bufio"
+    "bytes"
+    "crypto/sha1"
+    "encoding/base64"
+    "encoding/binary"
+    "errors"
+    "io"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+// wsPeer 是测试中服务器端的WebSocket连接
+// 服务器发送的帧不带掩码，并记录客户端帧是否带掩码
+type wsPeer struct {
+    conn net.Conn
+    br   *bufio.Reader
+}
+
+// wsFrame 是wsPeer读取到的一个帧
+type wsFrame struct {
+    fin       bool
+    opcode    byte
+    masked    bool
+    lengthLen int // 扩展长度字段的字节数：0、2或8
+    payload   []byte
+}
+
+// upgradeWebSocket 完成服务器端握手并接管连接
+func upgradeWebSocket(t *testing.T, w http.ResponseWriter, r *http.Request) *wsPeer {
+    t.Helper()
+    if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || r.Header.Get("Sec-WebSocket-Version") != "13" {
+        http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+        return nil
+    }
+    sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + websocketGUID))
+    conn, rw, err := w.(http.Hijacker).Hijack()
+    if err != nil {
+        t.Errorf("hijack: %v", err)
+        return nil
+    }
+    io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\n"+
+        "Upgrade: websocket\r\n"+
+        "Connection: Upgrade\r\n"+
+        "Sec-WebSocket-Accept: "+base64.StdEncoding.EncodeToString(sum[:])+"\r\n\r\n")
+    return &wsPeer{conn: conn, br: rw.Reader}
+}
+
+func (p *wsPeer) writeFrame(fin bool, opcode byte, payload []byte) error {
+    first := opcode
+    if fin {
+        first |= 0x80
+    }
+    header := []byte{first}
+    switch n := len(payload); {
+    case n < 126:
+        header = append(header, byte(n))
+    case n <= 0xFFFF:
+        header = append(header, 126, 0, 0)
+        binary.BigEndian.PutUint16(header[2:], uint16(n))
+    default:
+        header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+        binary.BigEndian.PutUint64(header[2:], uint64(n))
+    }
+    _, err := p.conn.Write(append(header, payload...))
+    return err
+}
+
+func (p *wsPeer) readFrame() (wsFrame, error) {
+    var f wsFrame
+    var head [2]byte
+    if _, err := io.ReadFull(p.br, head[:]); err != nil {
+        return f, err
+    }
+    f.fin = head[0]&0x80 != 0
+    f.opcode = head[0] & 0x0F
+    f.masked = head[1]&0x80 != 0
+    length := uint64(head[1] & 0x7F)
+    switch length {
+    case 126:
+        var ext [2]byte
+        if _, err := io.ReadFull(p.br, ext[:]); err != nil {
+            return f, err
+        }
+        length, f.lengthLen = uint64(binary.BigEndian.Uint16(ext[:])), 2
+    case 127:
+        var ext [8]byte
+        if _, err := io.ReadFull(p.br, ext[:]); err != nil {
+            return f, err
+        }
+        length, f.lengthLen = binary.BigEndian.Uint64(ext[:]), 8
+    }
+    var mask [4]byte
+    if f.masked {
+        if _, err := io.ReadFull(p.br, mask[:]); err != nil {
+            return f, err
+        }
+    }
+    f.payload = make([]byte, length)
+    if _, err := io.ReadFull(p.br, f.payload); err != nil {
+        return f, err
+    }
+    for i := range f.payload {
+        f.payload[i] ^= mask[i%4]
+    }
+    return f, nil
+}
+
+// newWebSocketServer 启动一个在每个连接上运行handler的WebSocket服务器
+// 返回ws://地址
+func newWebSocketServer(t *testing.T, handler func(p *wsPeer)) string {
+    t.Helper()
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if p := upgradeWebSocket(t, w, r); p != nil {
+            defer p.conn.Close()
+            handler(p)
+        }
+    }))
+    t.Cleanup(srv.Close)
+    return "ws" + strings.TrimPrefix(srv.URL, "http") + "/devtools/page/1"
+}
+
+// payloadOf 返回指定长度的可辨认测试数据
+func payloadOf(n int) []byte {
+    data := make([]byte, n)
+    for i := range data {
+        data[i] = byte('a' + i%26)
+    }
+    return data
+}
+
+func TestWebSocketMaskedFramesAndLengthForms(t *testing.T) {
+    sizes := []struct {
+        size      int
+        lengthLen int
+    }{
+        {0, 0},
+        {125, 0},
+        {126, 2},
+        {0xFFFF, 2},
+        {0x10000, 8},
+        {300000, 8},
+    }
+    frames := make(chan wsFrame, len(sizes))
+    url := newWebSocketServer(t, func(p *wsPeer) {
+        for range sizes {
+            f, err := p.readFrame()
+            if err != nil {
+                t.Errorf("server read: %v", err)
+                return
+            }
+            frames <- f
+            // 原样发回，验证客户端解析不带掩码的各种长度形式
+            if err := p.writeFrame(true, wsOpText, f.payload); err != nil {
+                t.Errorf("server write: %v", err)
+                return
+            }
+        }
+    })
+
+    ws, err := dialWebSocket(url, 5*time.Second)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer ws.close()
+    for _, tt := range sizes {
+        want := payloadOf(tt.size)
+        if err := ws.writeText(want); err != nil {
+            t.Fatalf("writeText(%d bytes): %v", tt.size, err)
+        }
+        f := <-frames
+        if !f.masked {
+            t.Errorf("%d byte frame is not masked", tt.size)
+        }
+        if !f.fin || f.opcode != wsOpText {
+            t.Errorf("%d byte frame: fin=%v opcode=%d", tt.size, f.fin, f.opcode)
+        }
+        if f.lengthLen != tt.lengthLen {
+            t.Errorf("%d byte frame used %d length bytes, want %d", tt.size, f.lengthLen, tt.lengthLen)
+        }
+        if !bytes.Equal(f.payload, want) {
+            t.Errorf("%d byte frame payload was not unmasked correctly", tt.size)
+        }
+        got, err := ws.readMessage()
+        if err != nil {
+            t.Fatalf("readMessage(%d bytes): %v", tt.size, err)
+        }
+        if !bytes.Equal(got, want) {
+            t.Errorf("echoed %d bytes, got %d bytes", tt.size, len(got))
+        }
+    }
+}
+
+func TestWebSocketFragmentationAndPing(t *testing.T) {
+    pong := make(chan wsFrame, 1)
+    url := newWebSocketServer(t, func(p *wsPeer) {
+        // 分片消息中间插入ping，控制帧可以出现在分片之间
+        p.writeFrame(false, wsOpText, []byte(`{"method":`))
+        p.writeFrame(true, wsOpPing, []byte("heartbeat"))
+        p.writeFrame(false, wsOpContinuation, payloadOf(70000))
+        p.writeFrame(true, wsOpContinuation, []byte(`}`))
+        p.writeFrame(true, wsOpPong, nil)
+        p.writeFrame(true, wsOpBinary, []byte("second"))
+        f, err := p.readFrame()
+        if err != nil {
+            t.Errorf("server read: %v", err)
+            return
+        }
+        pong <- f
+        io.Copy(io.Discard, p.br)
+    })
+
+    ws, err := dialWebSocket(url, 5*time.Second)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer ws.close()
+    got, err := ws.readMessage()
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := append(append([]byte(`{"method":`), payloadOf(70000)...), '}')
+    if !bytes.Equal(got, want) {
+        t.Errorf("reassembled %d bytes, want %d", len(got), len(want))
+    }
+    got, err = ws.readMessage()
+    if err != nil || string(got) != "second" {
+        t.Errorf("second message = %q, %v", got, err)
+    }
+    select {
+    case f := <-pong:
+        if f.opcode != wsOpPong || !f.masked || string(f.payload) != "heartbeat" {
+            t.Errorf("ping reply = opcode %d masked %v payload %q", f.opcode, f.masked, f.payload)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("no pong received")
+    }
+}
+
+func TestWebSocketClose(t *testing.T) {
+    // 服务器发起关闭：客户端回复关闭帧，readMessage返回io.EOF
+    reply := make(chan wsFrame, 1)
+    url := newWebSocketServer(t, func(p *wsPeer) {
+        p.writeFrame(true, wsOpClose, []byte{0x03, 0xE8})
+        f, err := p.readFrame()
+        if err != nil {
+            t.Errorf("server read: %v", err)
+            return
+        }
+        reply <- f
+    })
+    ws, err := dialWebSocket(url, 5*time.Second)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := ws.readMessage(); !errors.Is(err, io.EOF) {
+        t.Errorf("readMessage after close frame = %v, want io.EOF", err)
+    }
+    if f := <-reply; f.opcode != wsOpClose || !f.masked {
+        t.Errorf("close reply = opcode %d masked %v", f.opcode, f.masked)
+    }
+    ws.close()
+
+    // 客户端发起关闭：先发送关闭帧再断开连接
+    closed := make(chan wsFrame, 1)
+    url = newWebSocketServer(t, func(p *wsPeer) {
+        f, err := p.readFrame()
+        if err != nil {
+            t.Errorf("server read: %v", err)
+            return
+        }
+        closed <- f
+        if _, err := p.readFrame(); err == nil {
+            t.Error("connection still open after close")
+        }
+    })
+    ws, err = dialWebSocket(url, 5*time.Second)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := ws.close(); err != nil {
+        t.Fatal(err)
+    }
+    if f := <-closed; f.opcode != wsOpClose {
+        t.Errorf("first frame after close = opcode %d, want close", f.opcode)
+    }
+}
+
+func TestWebSocketHandshakeErrors(t *testing.T) {
+    if _, err := dialWebSocket("wss://127.0.0.1/devtools", time.Second); err == nil {
+        t.Error("wss scheme should be rejected")
+    }
+
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        http.Error(w, "no upgrade", http.StatusNotFound)
+    }))
+    defer srv.Close()
+    if _, err := dialWebSocket("ws"+strings.TrimPrefix(srv.URL, "http"), time.Second); err == nil {
+        t.Error("non-101 response should fail the handshake")
+    }
+
+    bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        conn, _, _ := w.(http.Hijacker).Hijack()
+        defer conn.Close()
+        io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\n"+
+            "Upgrade: websocket\r\nConnection: Upgrade\r\n"+
+            "Sec-WebSocket-Accept: invalid\r\n\r\n")
+    }))
+    defer bad.Close()
+    if _, err := dialWebSocket("ws"+strings.TrimPrefix(bad.URL, "http"), time.Second); err == nil {
+        t.Error("invalid Sec-WebSocket-Accept should fail the handshake")
+    }
+}